@@ -0,0 +1,83 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/types"
+)
+
+// Args lists the arguments of a field
+type Args struct {
+	field string
+}
+
+var argsPattern = regexp.MustCompile(`^args(?: ([_A-Za-z][_0-9A-Za-z]*))?$`)
+
+func testArgs(input string) (Command, error) {
+	match := argsPattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	return &Args{match[1]}, nil
+}
+
+// Execute implements the Command interface
+func (c Args) Execute(s types.Session) error {
+	field, err := c.getField(s)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+
+	fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", "NAME", "TYPE", "DEFAULT", "REQUIRED", "DESCRIPTION"))
+
+	for _, arg := range field.Args {
+		required := "no"
+		if arg.IsRequired() {
+			required = "yes"
+		}
+
+		fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", arg.Name, arg.GetHumanTypeName(), arg.DefaultValue, required, arg.Description))
+	}
+
+	tw.Flush()
+
+	return nil
+}
+
+// getField gets the named field of the current node, or the current node's
+// own field when no name is given
+func (c Args) getField(s types.Session) (*introspection.Field, error) {
+	if c.field == "" {
+		field, err := s.Schema().GetNodeField(s.RootQuery())
+		if err != nil {
+			return nil, err
+		}
+
+		if field == nil {
+			return nil, errors.New("The root of a query has no arguments, use `args <field>` to list a field's arguments")
+		}
+
+		return field, nil
+	}
+
+	typ, err := s.Schema().GetType(s.RootQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	field, ok := typ.GetField(c.field)
+	if !ok {
+		return nil, fmt.Errorf("Missing field %q from type %q", c.field, typ.Name)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/types"
+)
+
+// Describe prints the definition of a type
+type Describe struct {
+	name string
+}
+
+var describePattern = regexp.MustCompile(`^(?:describe|type)(?: ([_A-Za-z][_0-9A-Za-z]*))?$`)
+
+func testDescribe(input string) (Command, error) {
+	match := describePattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	return &Describe{match[1]}, nil
+}
+
+// Execute implements the Command interface
+func (c Describe) Execute(s types.Session) error {
+	typ, err := s.Schema().GetType(s.RootQuery())
+	if err != nil {
+		return err
+	}
+
+	if c.name != "" {
+		if typ, err = describedType(s.Schema(), typ, c.name); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println(introspection.PrintType(typ))
+
+	if typ.Kind == "INTERFACE" && len(typ.PossibleTypes) > 0 {
+		names := make([]string, 0, len(typ.PossibleTypes))
+		for _, ref := range typ.PossibleTypes {
+			names = append(names, ref.Name)
+		}
+
+		fmt.Printf("\n# Implemented by %s\n", strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// describedType finds the type of a field of the current type, or else a type
+// with the given name
+func describedType(schema *introspection.Schema, current *introspection.FullType, name string) (*introspection.FullType, error) {
+	if field, ok := current.GetField(name); ok {
+		typ, ok := schema.LookupType(field.GetTypeName())
+		if !ok {
+			return nil, fmt.Errorf("Missing type %q", field.GetTypeName())
+		}
+
+		return typ, nil
+	}
+
+	typ, ok := schema.LookupType(name)
+	if !ok {
+		return nil, fmt.Errorf("No type or field of %q named %q exists", current.Name, name)
+	}
+
+	return typ, nil
+}
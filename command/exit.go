@@ -1,11 +1,14 @@
 package command
 
 import (
-	"os"
+	"errors"
 
 	"github.com/jclem/graphsh/types"
 )
 
+// ErrExit is returned by the Exit command to ask the session to end
+var ErrExit = errors.New("Exit")
+
 // Exit exits the shell
 type Exit struct{}
 
@@ -19,6 +22,5 @@ func testExit(input string) (Command, error) {
 
 // Execute implements the Command interface
 func (c Exit) Execute(s types.Session) error {
-	os.Exit(0)
-	return nil
+	return ErrExit
 }
@@ -22,6 +22,19 @@ type helpInfo struct {
 }
 
 var helpMap = map[string]helpInfo{
+	"args": {
+		usage: "args [<field>]",
+		description: `Lists the arguments of a field of the current query node
+
+Lists the arguments of the current query node's own field when no field is given.`,
+	},
+	"describe": {
+		usage: "describe [<Type>|<field>] | type [<Type>|<field>]",
+		description: `Prints the definition of a type in the schema definition language
+
+Describes the current query node's type by default, or the type of one of its
+fields, or any type in the schema by name.`,
+	},
 	"exit": {
 		usage:       "exit",
 		description: "Exits the graphsh shell",
@@ -30,13 +43,41 @@ var helpMap = map[string]helpInfo{
 		usage:       "help | help <command>",
 		description: "Displays help for a command",
 	},
+	"history": {
+		usage: "history | history <pattern>",
+		description: `Lists previously entered commands, optionally only those containing a pattern
+
+Use "!!" to re-execute the last command, "!<n>" to re-execute command number n,
+"!-<n>" to re-execute the nth previous command, or "!<prefix>" to re-execute
+the last command starting with a prefix.`,
+	},
 	"ls": {
 		usage:       "ls",
 		description: "Lists the fields for the current query node",
 	},
+	"mutation": {
+		usage: "mutation",
+		description: `Switches to exploring and executing mutations, starting from the mutation root
+
+Mutations ask for confirmation before they are executed.`,
+	},
 	"on": {
-		usage:       "on <ConcreteType>",
-		description: "Applies a concrete type to the current query node",
+		usage: "on [<ConcreteType>|?]",
+		description: `Applies a concrete type to the current query node
+
+The type must be a possible type of the node's interface or union type, or an
+interface implemented by its object type. Pass "?" to list the types that can
+be applied, or nothing to remove the concrete type.`,
+	},
+	"path-to": {
+		usage: "path-to [-n <count>] <TypeName> [<number>]",
+		description: `Lists the shortest traversal paths from the current query node to a type
+
+Paths are numbered, shortest first, and each line of a path is a command to
+run in turn: a path traversal, or "on" to apply a concrete type. Required
+arguments are given as variables named after them, which can be defined with
+"set". Pass -n to list more or fewer than 10 paths, or the number of a path to
+run all of its commands.`,
 	},
 	"pp": {
 		usage:       "pp",
@@ -46,6 +87,51 @@ var helpMap = map[string]helpInfo{
 		usage:       "pq",
 		description: "Prints the current query",
 	},
+	"query": {
+		usage:       "query",
+		description: "Switches to exploring and executing queries, starting from the query root",
+	},
+	"schema": {
+		usage: "schema refresh",
+		description: `Introspects the schema again, updating the schema cache
+
+When the schema was loaded with --schema, the file is read again instead.`,
+	},
+	"sdl": {
+		usage:       "sdl",
+		description: "Prints the schema in the GraphQL schema definition language",
+	},
+	"search": {
+		usage: "search [--reachable] <pattern>",
+		description: `Finds types, fields, arguments, input fields, and enum values by name or description
+
+A pattern surrounded by slashes, such as /^issue/, is a regular expression.
+Any other pattern matches names containing its characters in order, and
+descriptions containing it, ignoring case. The best matches are listed first.
+
+With --reachable, only the types reachable from the current query node are
+searched.`,
+	},
+	"set": {
+		usage: "set $<name> <value>",
+		description: `Sets a variable that can be referenced in paths and queries
+
+For example, after "set $owner "jclem"", use ".repositoryOwner(login: $owner)"`,
+	},
+	"subscription": {
+		usage: "subscription",
+		description: `Switches to exploring and executing subscriptions, starting from the subscription root
+
+Executing a subscription streams each event until it completes or ^C is pressed.`,
+	},
+	"unset": {
+		usage:       "unset $<name>",
+		description: "Removes a variable",
+	},
+	"vars": {
+		usage:       "vars",
+		description: "Lists variables and their values",
+	},
 	".": {
 		usage: ".<field>[...]",
 		description: `Traverses through fields of the current query
@@ -64,6 +150,17 @@ For example, to query the current node's URL and its app name: { url, app { name
 	},
 }
 
+// Names returns the name of every command, sorted
+func Names() []string {
+	names := make([]string, 0, len(helpMap))
+	for name := range helpMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 var helpPattern = regexp.MustCompile(`^h(?:elp)?( .+)?$`)
 
 func testHelp(input string) (Command, error) {
@@ -79,17 +176,11 @@ func testHelp(input string) (Command, error) {
 // Execute implements the Command interface
 func (c Help) Execute(s types.Session) error {
 	if c.command == "" {
-		helpKeys := make([]string, 0, len(helpMap))
-		for key := range helpMap {
-			helpKeys = append(helpKeys, key)
-		}
-		sort.Strings(helpKeys)
-
 		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
 
 		fmt.Fprintln(tw, fmt.Sprintf("%s\t%s", "COMMAND", "DESCRIPTION"))
 
-		for _, key := range helpKeys {
+		for _, key := range Names() {
 			helpInfo := helpMap[key]
 			description := strings.Split(helpInfo.description, "\n")[0]
 			fmt.Fprintln(tw, fmt.Sprintf("%s\t%s", key, description))
@@ -103,8 +194,7 @@ func (c Help) Execute(s types.Session) error {
 	helpInfo, ok := helpMap[c.command]
 
 	if !ok {
-		fmt.Fprintln(os.Stderr, fmt.Sprintf("No such command %q exists", c.command))
-		return nil
+		return fmt.Errorf("No such command %q exists", c.command)
 	}
 
 	fmt.Printf("Usage: %s\n\n%s\n", helpInfo.usage, helpInfo.description)
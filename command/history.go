@@ -0,0 +1,39 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jclem/graphsh/types"
+)
+
+// History lists and searches previously entered commands
+type History struct {
+	pattern string
+}
+
+var historyPattern = regexp.MustCompile(`^history(?: (.+))?$`)
+
+func testHistory(input string) (Command, error) {
+	match := historyPattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	return &History{match[1]}, nil
+}
+
+// Execute implements the Command interface
+func (c History) Execute(s types.Session) error {
+	pattern := strings.ToLower(c.pattern)
+
+	for i, entry := range s.History() {
+		if strings.Contains(strings.ToLower(entry), pattern) {
+			fmt.Printf("%5d  %s\n", i+1, entry)
+		}
+	}
+
+	return nil
+}
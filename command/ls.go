@@ -3,9 +3,9 @@ package command
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
-	"github.com/jclem/graphsh/introspection"
 	"github.com/jclem/graphsh/types"
 )
 
@@ -22,7 +22,12 @@ func testLs(input string) (Command, error) {
 
 // Execute implements the Command interface
 func (c Ls) Execute(s types.Session) error {
-	fields, err := introspection.GetFields(s.Client(), s.RootQuery())
+	fields, err := s.Schema().GetFields(s.RootQuery())
+	if err != nil {
+		return err
+	}
+
+	concreteFields, err := s.Schema().GetConcreteFields(s.RootQuery())
 	if err != nil {
 		return err
 	}
@@ -35,6 +40,12 @@ func (c Ls) Execute(s types.Session) error {
 		fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s", field.Name, field.GetHumanTypeName(), field.Description))
 	}
 
+	// Fields of possible types are only available after applying a concrete type
+	for _, field := range concreteFields {
+		name := fmt.Sprintf("%s (on %s)", field.Name, strings.Join(field.Types, ", "))
+		fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s", name, field.GetHumanTypeName(), field.Description))
+	}
+
 	tw.Flush()
 
 	return nil
@@ -1,7 +1,9 @@
 package command
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/jclem/graphsh/types"
 )
@@ -11,7 +13,7 @@ type On struct {
 	concreteType string
 }
 
-var onTest = regexp.MustCompile("^on(?: ([a-zA-Z0-9_-]+))?$")
+var onTest = regexp.MustCompile(`^on(?: ([a-zA-Z0-9_-]+|\?))?$`)
 
 func testOn(input string) (Command, error) {
 	match := onTest.FindStringSubmatch(input)
@@ -25,6 +27,39 @@ func testOn(input string) (Command, error) {
 
 // Execute implements the Command interface
 func (o On) Execute(s types.Session) error {
-	s.CurrentQuery().ConcreteType = o.concreteType
-	return nil
+	if o.concreteType == "" {
+		s.CurrentQuery().ConcreteType = ""
+		return nil
+	}
+
+	typ, err := s.Schema().GetNodeType(s.RootQuery())
+	if err != nil {
+		return err
+	}
+
+	possibleTypes, err := s.Schema().GetPossibleTypes(s.RootQuery())
+	if err != nil {
+		return err
+	}
+
+	if o.concreteType == "?" {
+		for _, name := range possibleTypes {
+			fmt.Println(name)
+		}
+
+		return nil
+	}
+
+	for _, name := range possibleTypes {
+		if name == o.concreteType {
+			s.CurrentQuery().ConcreteType = o.concreteType
+			return nil
+		}
+	}
+
+	if len(possibleTypes) == 0 {
+		return fmt.Errorf("No concrete types can be applied to type %q", typ.Name)
+	}
+
+	return fmt.Errorf("Type %q can not be applied to type %q, expected one of: %s", o.concreteType, typ.Name, strings.Join(possibleTypes, ", "))
 }
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/jclem/graphsh/types"
+)
+
+// Operation switches the root operation type of the session
+type Operation struct {
+	operation string
+}
+
+func testOperation(input string) (Command, error) {
+	if input == "query" || input == "mutation" || input == "subscription" {
+		return &Operation{input}, nil
+	}
+
+	return nil, nil
+}
+
+// Execute implements the Command interface
+func (c Operation) Execute(s types.Session) error {
+	if s.RootQuery().Name == c.operation {
+		return nil
+	}
+
+	root := querybuilder.NewRootOperation(c.operation)
+
+	if _, err := s.Schema().GetType(root); err != nil {
+		return err
+	}
+
+	s.SetRootQuery(root)
+	s.SetCurrentQuery(root)
+
+	return nil
+}
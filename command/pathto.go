@@ -0,0 +1,146 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/jclem/graphsh/types"
+)
+
+// defaultPathLimit is the number of paths listed without -n
+const defaultPathLimit = 10
+
+// PathTo finds traversal paths from the current node to a type
+type PathTo struct {
+	typeName string
+	limit    int
+
+	// index is the 1-based index of the path to traverse, or 0 to list paths
+	index int
+}
+
+var pathToPattern = regexp.MustCompile(`^path-to(?: -n (\d+))? ([_A-Za-z][_0-9A-Za-z]*)(?: (\d+))?$`)
+
+func testPathTo(input string) (Command, error) {
+	match := pathToPattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	c := &PathTo{typeName: match[2], limit: defaultPathLimit}
+
+	if match[1] != "" {
+		c.limit, _ = strconv.Atoi(match[1])
+	}
+
+	if match[3] != "" {
+		c.index, _ = strconv.Atoi(match[3])
+	}
+
+	if c.limit < 1 {
+		return nil, fmt.Errorf("Invalid number of paths %q", match[1])
+	}
+
+	if match[3] != "" && c.index < 1 {
+		return nil, fmt.Errorf("Invalid path number %q", match[3])
+	}
+
+	return c, nil
+}
+
+// Execute implements the Command interface
+func (c PathTo) Execute(s types.Session) error {
+	typ, err := s.Schema().GetType(s.RootQuery())
+	if err != nil {
+		return err
+	}
+
+	if typ.Name == c.typeName {
+		return fmt.Errorf("The current node is already of type %q", c.typeName)
+	}
+
+	limit := c.limit
+	if c.index > limit {
+		limit = c.index
+	}
+
+	paths, err := s.Schema().PathsTo(typ.Name, c.typeName, limit)
+	if err != nil {
+		return err
+	}
+
+	if len(paths) == 0 {
+		return fmt.Errorf("No paths from type %q to type %q", typ.Name, c.typeName)
+	}
+
+	if c.index == 0 {
+		// Each line of a path is a command, so paths can also be followed by hand
+		for i, path := range paths {
+			for j, command := range path.Commands() {
+				if j == 0 {
+					fmt.Printf("%5d  %s\n", i+1, command)
+				} else {
+					fmt.Printf("%5s  %s\n", "", command)
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if c.index > len(paths) {
+		return fmt.Errorf("Path %d to type %q does not exist", c.index, c.typeName)
+	}
+
+	return traversePath(s, paths[c.index-1])
+}
+
+// traversePath traverses a path found in the schema, applying its concrete
+// types along the way
+func traversePath(s types.Session, path introspection.Path) error {
+	current := s.CurrentQuery()
+	concreteType := current.ConcreteType
+
+	if path[0].Field == "" {
+		if err := (On{path[0].ConcreteType}).Execute(s); err != nil {
+			return err
+		}
+
+		path = path[1:]
+	}
+
+	if len(path) == 0 {
+		return nil
+	}
+
+	var head, tail *querybuilder.Query
+
+	for _, step := range path {
+		args := make(map[string]interface{}, len(step.Args))
+		for _, arg := range step.Args {
+			args[arg.Name] = querybuilder.Variable(arg.Variable)
+		}
+
+		node := querybuilder.NewQuery(step.Field, args)
+		node.ConcreteType = step.ConcreteType
+
+		if head == nil {
+			head = node
+		} else {
+			tail.AddChild(node)
+		}
+
+		tail = node
+	}
+
+	if err := (Traverse{head: head, tail: tail}).Execute(s); err != nil {
+		current.ConcreteType = concreteType
+		return err
+	}
+
+	return nil
+}
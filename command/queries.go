@@ -29,13 +29,13 @@ func getSchema(s types.Session, typename string) (parsedSchema, error) {
 				description
 			}
 		}
-	}`, typename))
+	}`, typename), nil)
 
 	if err != nil {
 		return schema, err
 	}
 
-	if err := json.Unmarshal(schemaResult, &schema); err != nil {
+	if err := json.Unmarshal(schemaResult.Data, &schema.Data); err != nil {
 		return schema, err
 	}
 
@@ -53,7 +53,7 @@ func getTypename(s types.Session) (string, error) {
 		Data map[string]interface{}
 	}
 
-	if err := json.Unmarshal(queryResp, &payload); err != nil {
+	if err := json.Unmarshal(queryResp.Data, &payload.Data); err != nil {
 		return "", err
 	}
 
@@ -1,13 +1,21 @@
 package command
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"regexp"
 
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/querybuilder"
 	"github.com/jclem/graphsh/types"
 )
 
+// ErrGraphQL is returned when a query's response contains GraphQL errors
+var ErrGraphQL = errors.New("Query returned errors")
+
 var headerPattern = regexp.MustCompile("^(.+): (.+)$")
 var queryPattern = regexp.MustCompile("^{.+}$")
 
@@ -27,27 +35,128 @@ func testQuery(input string) (Command, error) {
 
 // Execute implements the Command interface
 func (c Query) Execute(s types.Session) error {
-	body, err := executeQuery(s, c.query)
+	fullQuery, variables, err := buildQuery(s, c.query)
 	if err != nil {
 		return err
 	}
 
-	var parsed map[string]interface{}
-	if err := json.Unmarshal(body, &parsed); err != nil {
+	if s.RootQuery().Name == "mutation" {
+		ok, err := s.Confirm("Execute this mutation?")
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errors.New("Mutation cancelled")
+		}
+	}
+
+	if s.RootQuery().Name == "subscription" {
+		return executeSubscription(s, fullQuery, variables)
+	}
+
+	resp, err := s.Client().Query(fullQuery, variables)
+	if err != nil {
 		return err
 	}
 
-	json, err := json.MarshalIndent(parsed, "", "  ")
+	if err := printData(os.Stdout, resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		printErrors(os.Stderr, s.RootQuery(), resp.Errors, useColor())
+		return ErrGraphQL
+	}
+
+	return nil
+}
+
+func executeQuery(s types.Session, query string) (*graphql.Response, error) {
+	fullQuery, variables, err := buildQuery(s, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Client().Query(fullQuery, variables)
+}
+
+// executeSubscription streams each event of a subscription until it completes
+// or is interrupted
+func executeSubscription(s types.Session, fullQuery string, variables map[string]interface{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if s.Interactive() {
+		fmt.Fprintln(os.Stderr, "Subscribed, press ^C to stop")
+	}
+
+	hadErrors := false
+
+	err := s.Subscriber().Subscribe(ctx, fullQuery, variables, func(resp *graphql.Response) error {
+		if err := printData(os.Stdout, resp); err != nil {
+			return err
+		}
+
+		if len(resp.Errors) > 0 {
+			printErrors(os.Stderr, s.RootQuery(), resp.Errors, useColor())
+			hadErrors = true
+		}
+
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(string(json))
+	if hadErrors {
+		return ErrGraphQL
+	}
 
 	return nil
 }
 
-func executeQuery(s types.Session, query string) ([]byte, error) {
-	fullQuery := s.RootQuery().WithQuery(query)
-	return s.Client().Query(fullQuery)
+// buildQuery validates a selection in the current query node and builds the
+// full query, along with the values of the variables it references
+func buildQuery(s types.Session, query string) (string, map[string]interface{}, error) {
+	selections, err := querybuilder.ParseSelections(query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := s.Schema().Validate(s.RootQuery(), selections, s.Variables()); err != nil {
+		return "", nil, err
+	}
+
+	definitions, err := s.Schema().GetVariableDefinitions(s.RootQuery(), selections)
+	if err != nil {
+		return "", nil, err
+	}
+
+	variables := map[string]interface{}{}
+
+	for _, definition := range definitions {
+		value, ok := s.Variables()[definition.Name]
+		if !ok {
+			return "", nil, fmt.Errorf("Undefined variable $%s, use `set $%s <value>` to define it", definition.Name, definition.Name)
+		}
+
+		if variables[definition.Name], err = querybuilder.ToJSON(value); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return s.RootQuery().WithQuery(query, definitions...), variables, nil
 }
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/querybuilder"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorRed   = "\x1b[1;31m"
+	colorCyan  = "\x1b[36m"
+	colorDim   = "\x1b[2m"
+)
+
+// printData prints the data of a response as indented JSON
+func printData(w io.Writer, resp *graphql.Response) error {
+	if !resp.HasData() {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(map[string]json.RawMessage{"data": resp.Data}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, string(data))
+
+	return nil
+}
+
+// printErrors prints the errors of a response, mapping each error's path back
+// to a path relative to the given query
+func printErrors(w io.Writer, root *querybuilder.Query, errs []graphql.Error, color bool) {
+	paint := func(code string, s string) string {
+		if !color {
+			return s
+		}
+
+		return code + s + colorReset
+	}
+
+	for _, e := range errs {
+		fmt.Fprintf(w, "%s %s\n", paint(colorRed, "Error:"), e.Message)
+
+		if len(e.Path) > 0 {
+			fmt.Fprintf(w, "  %s %s\n", paint(colorDim, "path:"), paint(colorCyan, root.ResponsePath(e.Path)))
+		}
+
+		if len(e.Locations) > 0 {
+			locations := make([]string, 0, len(e.Locations))
+			for _, l := range e.Locations {
+				locations = append(locations, fmt.Sprintf("line %d, column %d", l.Line, l.Column))
+			}
+
+			fmt.Fprintf(w, "  %s %s\n", paint(colorDim, "location:"), strings.Join(locations, "; "))
+		}
+
+		if len(e.Extensions) > 0 {
+			if extensions, err := json.Marshal(e.Extensions); err == nil {
+				fmt.Fprintf(w, "  %s %s\n", paint(colorDim, "extensions:"), extensions)
+			}
+		}
+	}
+}
+
+// useColor is whether output to stderr should be colored
+func useColor() bool {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return !noColor && readline.IsTerminal(int(os.Stderr.Fd()))
+}
@@ -0,0 +1,42 @@
+package command
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintErrors(t *testing.T) {
+	root := querybuilder.NewRootQuery()
+	queries, err := querybuilder.ParsePath(`.repository(owner: "jclem", name: "graphsh")`)
+	require.NoError(t, err)
+	root.AddChild(queries[0])
+
+	var out bytes.Buffer
+	printErrors(&out, root, []graphql.Error{{
+		Message:    "Field 'nmae' doesn't exist",
+		Locations:  []graphql.Location{{Line: 3, Column: 1}},
+		Path:       []interface{}{"repository", "nmae"},
+		Extensions: map[string]interface{}{"code": "undefinedField"},
+	}}, false)
+
+	assert.Equal(t, `Error: Field 'nmae' doesn't exist
+  path: .query.repository(name: "graphsh", owner: "jclem").nmae
+  location: line 3, column 1
+  extensions: {"code":"undefinedField"}
+`, out.String())
+}
+
+func TestPrintData(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printData(&out, &graphql.Response{Data: []byte(`{"b": 1, "a": [true]}`)}))
+	assert.Equal(t, "{\n  \"data\": {\n    \"b\": 1,\n    \"a\": [\n      true\n    ]\n  }\n}\n", out.String())
+
+	out.Reset()
+	require.NoError(t, printData(&out, &graphql.Response{Data: []byte("null")}))
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,21 @@
+package command
+
+import (
+	"github.com/jclem/graphsh/types"
+)
+
+// Schema manages the session's schema
+type Schema struct{}
+
+func testSchema(input string) (Command, error) {
+	if input == "schema refresh" {
+		return &Schema{}, nil
+	}
+
+	return nil, nil
+}
+
+// Execute implements the Command interface
+func (c Schema) Execute(s types.Session) error {
+	return s.RefreshSchema()
+}
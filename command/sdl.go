@@ -0,0 +1,26 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/types"
+)
+
+// SDL prints the schema in the GraphQL schema definition language
+type SDL struct{}
+
+func testSDL(input string) (Command, error) {
+	if input == "sdl" {
+		return &SDL{}, nil
+	}
+
+	return nil, nil
+}
+
+// Execute implements the Command interface
+func (c SDL) Execute(s types.Session) error {
+	fmt.Print(introspection.PrintSchema(s.Schema()))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/jclem/graphsh/types"
+)
+
+// Search finds types, fields, arguments, and enum values in the schema
+type Search struct {
+	pattern   string
+	reachable bool
+}
+
+var searchPattern = regexp.MustCompile(`^search(?: (--reachable))? (.+)$`)
+
+func testSearch(input string) (Command, error) {
+	match := searchPattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	return &Search{pattern: match[2], reachable: match[1] != ""}, nil
+}
+
+// Execute implements the Command interface
+func (c Search) Execute(s types.Session) error {
+	var names []string
+
+	if c.reachable {
+		typ, err := s.Schema().GetType(s.RootQuery())
+		if err != nil {
+			return err
+		}
+
+		names = s.Schema().ReachableTypes(typ.Name)
+	}
+
+	results, err := s.Schema().Search(c.pattern, names)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+
+	fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s", "NAME", "TYPE", "DESCRIPTION"))
+
+	for _, result := range results {
+		fmt.Fprintln(tw, fmt.Sprintf("%s\t%s\t%s", result.Path, result.Type.HumanName(), result.Description))
+	}
+
+	tw.Flush()
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/jclem/graphsh/types"
+)
+
+// Set defines a session variable
+type Set struct {
+	name  string
+	value querybuilder.Value
+}
+
+var setPattern = regexp.MustCompile(`^set \$([_A-Za-z][_0-9A-Za-z]*) (.+)$`)
+
+func testSet(input string) (Command, error) {
+	match := setPattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	value, err := querybuilder.ParseValue(match[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if containsVariable(value) {
+		return nil, errors.New("Variable values must not reference other variables")
+	}
+
+	return &Set{name: match[1], value: value}, nil
+}
+
+// Execute implements the Command interface
+func (c Set) Execute(s types.Session) error {
+	s.SetVariable(c.name, c.value)
+	return nil
+}
+
+func containsVariable(value querybuilder.Value) bool {
+	switch v := value.(type) {
+	case querybuilder.Variable:
+		return true
+	case querybuilder.ListValue:
+		for _, item := range v {
+			if containsVariable(item) {
+				return true
+			}
+		}
+	case querybuilder.ObjectValue:
+		for _, field := range v {
+			if containsVariable(field.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/jclem/graphsh/types"
+)
+
+// Unset removes a session variable
+type Unset struct {
+	name string
+}
+
+var unsetPattern = regexp.MustCompile(`^unset \$([_A-Za-z][_0-9A-Za-z]*)$`)
+
+func testUnset(input string) (Command, error) {
+	match := unsetPattern.FindStringSubmatch(input)
+
+	if len(match) == 0 {
+		return nil, nil
+	}
+
+	return &Unset{match[1]}, nil
+}
+
+// Execute implements the Command interface
+func (c Unset) Execute(s types.Session) error {
+	if _, ok := s.Variables()[c.name]; !ok {
+		return fmt.Errorf("No such variable $%s", c.name)
+	}
+
+	s.UnsetVariable(c.name)
+	return nil
+}
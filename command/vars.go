@@ -0,0 +1,44 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/jclem/graphsh/types"
+)
+
+// Vars lists session variables
+type Vars struct{}
+
+func testVars(input string) (Command, error) {
+	if input == "vars" {
+		return &Vars{}, nil
+	}
+
+	return nil, nil
+}
+
+// Execute implements the Command interface
+func (c Vars) Execute(s types.Session) error {
+	variables := s.Variables()
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+
+	fmt.Fprintln(tw, fmt.Sprintf("%s\t%s", "NAME", "VALUE"))
+
+	for _, name := range names {
+		fmt.Fprintln(tw, fmt.Sprintf("$%s\t%s", name, variables[name]))
+	}
+
+	tw.Flush()
+
+	return nil
+}
@@ -2,14 +2,15 @@ package graphql
 
 import (
 	"bytes"
-	"fmt"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strings"
 )
 
 // Querier is an interface that makes GraphQL requests
 type Querier interface {
-	Query(query string) ([]byte, error)
+	Query(query string, variables map[string]interface{}) (*Response, error)
 }
 
 // New creates a new Querier client
@@ -32,8 +33,17 @@ type newOptions struct {
 	Header   http.Header
 }
 
-func (c client) Query(query string) ([]byte, error) {
-	reqBody := []byte(fmt.Sprintf(`{"query":%q}`, query))
+type requestBody struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+func (c client) Query(query string, variables map[string]interface{}) (*Response, error) {
+	reqBody, err := json.Marshal(requestBody{Query: query, Variables: variables})
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequest("POST", c.endpoint, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, err
@@ -51,5 +61,22 @@ func (c client) Query(query string) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
-	return ioutil.ReadAll(resp.Body)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := ParseResponse(body)
+	if err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(body))}
+		}
+
+		return nil, err
+	}
+
+	response.StatusCode = resp.StatusCode
+	response.Header = resp.Header
+
+	return response, nil
 }
@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(status int, contentType string, body string) (*httptest.Server, *requestBody) {
+	var received requestBody
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("content-type", contentType)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+
+	return server, &received
+}
+
+func TestQuery(t *testing.T) {
+	server, received := newTestServer(200, "application/json", `{"data": {"viewer": {"login": "jclem"}}}`)
+	defer server.Close()
+
+	resp, err := New(server.URL, http.Header{}).Query("query($id: ID!) { viewer { login } }", map[string]interface{}{"id": "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"viewer": {"login": "jclem"}}`, string(resp.Data))
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("content-type"))
+	assert.Equal(t, map[string]interface{}{"id": "abc"}, received.Variables)
+}
+
+func TestQueryErrors(t *testing.T) {
+	server, _ := newTestServer(400, "application/json", `{"errors": [{"message": "Field 'nmae' doesn't exist", "locations": [{"line": 2, "column": 3}], "path": ["viewer", 0, "nmae"], "extensions": {"code": "undefinedField"}}]}`)
+	defer server.Close()
+
+	resp, err := New(server.URL, http.Header{}).Query("{ viewer { nmae } }", nil)
+	require.NoError(t, err)
+	assert.False(t, resp.HasData())
+	assert.Equal(t, 400, resp.StatusCode)
+	assert.Equal(t, []Error{{
+		Message:    "Field 'nmae' doesn't exist",
+		Locations:  []Location{{Line: 2, Column: 3}},
+		Path:       []interface{}{"viewer", float64(0), "nmae"},
+		Extensions: map[string]interface{}{"code": "undefinedField"},
+	}}, resp.Errors)
+}
+
+func TestQueryHTTPError(t *testing.T) {
+	server, _ := newTestServer(502, "text/html", "<html>Bad Gateway</html>")
+	defer server.Close()
+
+	_, err := New(server.URL, http.Header{}).Query("{ viewer { login } }", nil)
+	assert.EqualError(t, err, "HTTP 502 Bad Gateway: <html>Bad Gateway</html>")
+	assert.IsType(t, &HTTPError{}, err)
+}
+
+func TestQueryInvalidResponse(t *testing.T) {
+	server, _ := newTestServer(200, "text/html", "<html>Sign in</html>")
+	defer server.Close()
+
+	_, err := New(server.URL, http.Header{}).Query("{ viewer { login } }", nil)
+	assert.EqualError(t, err, `Expected a JSON response, but got "<html>Sign in</html>"`)
+
+	_, err = ParseResponse([]byte(`{"message": "Bad credentials"}`))
+	assert.EqualError(t, err, `Expected a GraphQL response with data or errors, but got "{\"message\": \"Bad credentials\"}"`)
+
+	// Truncating splits neither "é" nor "😀"
+	_, err = ParseResponse([]byte(strings.Repeat("x", 199) + "é"))
+	assert.EqualError(t, err, fmt.Sprintf("Expected a JSON response, but got %q", strings.Repeat("x", 199)+"…"))
+
+	_, err = ParseResponse([]byte(strings.Repeat("x", 198) + "😀😀"))
+	assert.EqualError(t, err, fmt.Sprintf("Expected a JSON response, but got %q", strings.Repeat("x", 198)+"…"))
+}
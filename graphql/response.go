@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// Response is the response to a GraphQL request
+type Response struct {
+	Data       json.RawMessage
+	Errors     []Error
+	Extensions json.RawMessage
+
+	// StatusCode and Header are from the HTTP response
+	StatusCode int
+	Header     http.Header
+}
+
+// Error is an error in a GraphQL response
+type Error struct {
+	Message    string
+	Locations  []Location
+	Path       []interface{}
+	Extensions map[string]interface{}
+}
+
+// Location is a location in a GraphQL query
+type Location struct {
+	Line   int
+	Column int
+}
+
+// HTTPError is returned when a request fails with a non-2xx status and no
+// GraphQL response
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP %s", e.Status)
+	}
+
+	return fmt.Sprintf("HTTP %s: %s", e.Status, truncate(e.Body))
+}
+
+// ParseResponse parses the body of a GraphQL response
+func ParseResponse(body []byte) (*Response, error) {
+	var resp Response
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("Expected a JSON response, but got %s", snippet(body))
+	}
+
+	if resp.Data == nil && resp.Errors == nil {
+		return nil, fmt.Errorf("Expected a GraphQL response with data or errors, but got %s", snippet(body))
+	}
+
+	return &resp, nil
+}
+
+// HasData is whether the response contains non-null data
+func (r Response) HasData() bool {
+	return len(r.Data) > 0 && string(r.Data) != "null"
+}
+
+// snippet returns the start of a response body, for error messages
+func snippet(body []byte) string {
+	s := strings.TrimSpace(string(body))
+
+	if s == "" {
+		return "an empty body"
+	}
+
+	return fmt.Sprintf("%q", truncate(s))
+}
+
+// truncate shortens a string to at most maxLength bytes, backing up to the
+// start of a rune so that multi-byte characters are not split
+func truncate(s string) string {
+	const maxLength = 200
+
+	if len(s) > maxLength {
+		end := maxLength
+		for end > 0 && !utf8.RuneStart(s[end]) {
+			end--
+		}
+
+		return s[:end] + "…"
+	}
+
+	return s
+}
@@ -0,0 +1,376 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Subscriber is an interface that makes GraphQL subscription requests
+type Subscriber interface {
+	// Subscribe starts a subscription, calling handler with each event until
+	// the server completes the subscription, handler returns an error, or the
+	// context is cancelled
+	Subscribe(ctx context.Context, query string, variables map[string]interface{}, handler func(*Response) error) error
+}
+
+// WebSocket subprotocols for GraphQL subscriptions
+const (
+	// ProtocolTransportWS is the graphql-transport-ws protocol
+	ProtocolTransportWS = "graphql-transport-ws"
+
+	// ProtocolLegacyWS is the legacy subscriptions-transport-ws protocol,
+	// which confusingly uses the "graphql-ws" subprotocol name
+	ProtocolLegacyWS = "graphql-ws"
+)
+
+// wsProtocol describes the message types of a GraphQL WebSocket protocol
+type wsProtocol struct {
+	subscribe string
+	next      string
+	stop      string
+	terminate string
+}
+
+var wsProtocols = map[string]wsProtocol{
+	ProtocolTransportWS: {subscribe: "subscribe", next: "next", stop: "complete"},
+	ProtocolLegacyWS:    {subscribe: "start", next: "data", stop: "stop", terminate: "connection_terminate"},
+}
+
+// maxMessageSize limits the size of a single WebSocket message
+const maxMessageSize = 32 << 20
+
+// handshakeTimeout limits how long connecting to the server may take
+const handshakeTimeout = 45 * time.Second
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewSubscriber creates a new Subscriber client
+//
+// An http or https endpoint is connected to as a ws or wss endpoint. The
+// headers are sent with the WebSocket handshake and in the connection_init
+// payload, both as-is and under a "headers" key. Connections go through the
+// proxy given by the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment
+// variables.
+func NewSubscriber(endpoint string, header http.Header) Subscriber {
+	return wsClient{
+		endpoint: endpoint,
+		header:   header,
+		proxy:    http.ProxyFromEnvironment,
+	}
+}
+
+type wsClient struct {
+	endpoint string
+	header   http.Header
+
+	// proxy gets the proxy for a request, like http.Transport's Proxy
+	proxy func(*http.Request) (*url.URL, error)
+}
+
+// subscriptionID identifies the single subscription made on a connection
+const subscriptionID = "1"
+
+func (c wsClient) Subscribe(ctx context.Context, query string, variables map[string]interface{}, handler func(*Response) error) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return err
+	}
+	defer conn.Close()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// Servers that choose no subprotocol are assumed to speak the current one
+	protocol, ok := wsProtocols[conn.Subprotocol()]
+	if conn.Subprotocol() == "" {
+		protocol, ok = wsProtocols[ProtocolTransportWS], true
+	}
+	if !ok {
+		return fmt.Errorf("Unsupported WebSocket subprotocol %q", conn.Subprotocol())
+	}
+
+	if err := c.init(conn); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(requestBody{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	if err := conn.writeMessage(wsMessage{ID: subscriptionID, Type: protocol.subscribe, Payload: payload}); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// Stop the subscription when the context is cancelled, which also unblocks
+	// any pending read by closing the connection
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop(conn, protocol)
+		case <-done:
+		}
+	}()
+
+	for {
+		msg, err := readWSMessage(conn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if isClosed(err) {
+			return errors.New("Subscription connection closed by the server")
+		}
+		if err != nil {
+			return err
+		}
+
+		switch msg.Type {
+		case protocol.next:
+			resp, err := ParseResponse(msg.Payload)
+			if err != nil {
+				return err
+			}
+
+			if err := handler(resp); err != nil {
+				stop(conn, protocol)
+				return err
+			}
+		case "error":
+			return subscriptionError(msg.Payload)
+		case "complete":
+			return nil
+		case "ping":
+			if err := conn.writeMessage(wsMessage{Type: "pong"}); err != nil {
+				return err
+			}
+		case "pong", "ka":
+		default:
+			return fmt.Errorf("Unexpected subscription message type %q", msg.Type)
+		}
+	}
+}
+
+// dial opens a WebSocket connection to the endpoint, offering the GraphQL
+// subprotocols
+//
+// Cancelling the context interrupts connecting at any point, including the
+// TLS and WebSocket handshakes, which the dialer itself only bounds by the
+// context's deadline. Errors caused by the context are reported as its error.
+func (c wsClient) dial(ctx context.Context) (*wsConn, error) {
+	u, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+
+	connected := make(chan struct{})
+	defer close(connected)
+
+	dialer := websocket.Dialer{
+		Proxy:            c.proxy,
+		Subprotocols:     []string{ProtocolTransportWS, ProtocolLegacyWS},
+		HandshakeTimeout: handshakeTimeout,
+		NetDialContext: func(dialCtx context.Context, network string, addr string) (net.Conn, error) {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(dialCtx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			go func() {
+				select {
+				case <-ctx.Done():
+					conn.Close()
+				case <-connected:
+				}
+			}()
+
+			return conn, nil
+		},
+	}
+
+	conn, _, err := dialer.DialContext(ctx, u.String(), c.header)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// The dialer's read deadline can expire just before the context does
+		if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+			return nil, context.DeadlineExceeded
+		}
+
+		return nil, err
+	}
+
+	conn.SetReadLimit(maxMessageSize)
+
+	return &wsConn{Conn: conn}, nil
+}
+
+// init sends connection_init and waits for the server's connection_ack
+func (c wsClient) init(conn *wsConn) error {
+	params := map[string]interface{}{}
+	headers := map[string]string{}
+
+	for name := range c.header {
+		if strings.EqualFold(name, "content-type") {
+			continue
+		}
+
+		params[name] = c.header.Get(name)
+		headers[name] = c.header.Get(name)
+	}
+
+	params["headers"] = headers
+
+	payload, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	if err := conn.writeMessage(wsMessage{Type: "connection_init", Payload: payload}); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := readWSMessage(conn)
+		if isClosed(err) {
+			return errors.New("Subscription connection closed by the server before it was acknowledged")
+		}
+		if err != nil {
+			return err
+		}
+
+		switch msg.Type {
+		case "connection_ack":
+			return nil
+		case "connection_error":
+			return subscriptionError(msg.Payload)
+		case "ping":
+			if err := conn.writeMessage(wsMessage{Type: "pong"}); err != nil {
+				return err
+			}
+		case "ka", "pong":
+		default:
+			return fmt.Errorf("Expected connection_ack, but got %q", msg.Type)
+		}
+	}
+}
+
+// stop asks the server to stop the subscription and closes the connection
+func stop(conn *wsConn, protocol wsProtocol) {
+	conn.writeMessage(wsMessage{ID: subscriptionID, Type: protocol.stop})
+
+	if protocol.terminate != "" {
+		conn.writeMessage(wsMessage{Type: protocol.terminate})
+	}
+
+	conn.Close()
+}
+
+// wsConn is a WebSocket connection that may be written to by both the
+// subscription and its cancellation, which the underlying connection does not
+// allow concurrently
+type wsConn struct {
+	*websocket.Conn
+
+	writeMu sync.Mutex
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (c *wsConn) writeMessage(msg wsMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	return c.WriteMessage(websocket.TextMessage, data)
+}
+
+// Close sends a close message and closes the connection, and may be called
+// more than once
+func (c *wsConn) Close() error {
+	c.closeOnce.Do(func() {
+		message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		c.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+		c.closeErr = c.Conn.Close()
+	})
+
+	return c.closeErr
+}
+
+func readWSMessage(conn *wsConn) (wsMessage, error) {
+	var msg wsMessage
+
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return msg, err
+	}
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return msg, fmt.Errorf("Invalid subscription message %s", snippet(data))
+	}
+
+	return msg, nil
+}
+
+// isClosed is whether a read failed because the server closed the connection
+func isClosed(err error) bool {
+	_, ok := err.(*websocket.CloseError)
+	return ok
+}
+
+// subscriptionError converts an error payload, which is a list of GraphQL
+// errors or a single error object depending on the protocol, into an error
+func subscriptionError(payload json.RawMessage) error {
+	var errs []Error
+	if err := json.Unmarshal(payload, &errs); err != nil {
+		var single Error
+		if err := json.Unmarshal(payload, &single); err != nil || single.Message == "" {
+			return fmt.Errorf("Subscription failed: %s", snippet(payload))
+		}
+
+		errs = []Error{single}
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+
+	return fmt.Errorf("Subscription failed: %s", strings.Join(messages, "; "))
+}
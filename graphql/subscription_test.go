@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newWSServer starts a WebSocket server that picks the given subprotocol and
+// hands each connection to serve, recording the messages serve receives
+func newWSServer(t *testing.T, protocol string, serve func(conn *wsConn, received chan<- wsMessage)) (*httptest.Server, chan wsMessage) {
+	received := make(chan wsMessage, 100)
+
+	upgrader := websocket.Upgrader{}
+	if protocol != "" {
+		upgrader.Subprotocols = []string{protocol}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "graphql-transport-ws, graphql-ws", r.Header.Get("Sec-WebSocket-Protocol"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		serve(&wsConn{Conn: conn}, received)
+	}))
+
+	return server, received
+}
+
+func expectMessage(t *testing.T, conn *wsConn, received chan<- wsMessage, typ string) wsMessage {
+	msg, err := readWSMessage(conn)
+	require.NoError(t, err)
+	assert.Equal(t, typ, msg.Type)
+	received <- msg
+	return msg
+}
+
+func send(t *testing.T, conn *wsConn, msg string) {
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func collect(t *testing.T, server *httptest.Server) ([]string, error) {
+	var events []string
+
+	header := http.Header{"Authorization": {"Bearer token"}, "Content-Type": {"application/json"}}
+	subscriber := NewSubscriber(server.URL, header)
+
+	err := subscriber.Subscribe(context.Background(), "subscription { issueUpdated(id: $id) { title } }", map[string]interface{}{"id": "1"}, func(resp *Response) error {
+		if len(resp.Errors) > 0 {
+			events = append(events, "error: "+resp.Errors[0].Message)
+		} else {
+			events = append(events, string(resp.Data))
+		}
+		return nil
+	})
+
+	return events, err
+}
+
+func TestSubscribeTransportWS(t *testing.T) {
+	server, received := newWSServer(t, ProtocolTransportWS, func(conn *wsConn, received chan<- wsMessage) {
+		init := expectMessage(t, conn, received, "connection_init")
+		assert.JSONEq(t, `{"Authorization": "Bearer token", "headers": {"Authorization": "Bearer token"}}`, string(init.Payload))
+		send(t, conn, `{"type": "connection_ack"}`)
+
+		subscribe := expectMessage(t, conn, received, "subscribe")
+		assert.Equal(t, "1", subscribe.ID)
+		assert.JSONEq(t, `{"query": "subscription { issueUpdated(id: $id) { title } }", "variables": {"id": "1"}}`, string(subscribe.Payload))
+
+		send(t, conn, `{"id": "1", "type": "next", "payload": {"data": {"title": "a"}}}`)
+		send(t, conn, `{"type": "ping"}`)
+		expectMessage(t, conn, received, "pong")
+		send(t, conn, `{"id": "1", "type": "next", "payload": {"data": null, "errors": [{"message": "oops"}]}}`)
+		send(t, conn, `{"id": "1", "type": "complete"}`)
+	})
+	defer server.Close()
+
+	events, err := collect(t, server)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"title": "a"}`, "error: oops"}, events)
+	assert.Len(t, received, 3)
+}
+
+func TestSubscribeLegacyWS(t *testing.T) {
+	server, _ := newWSServer(t, ProtocolLegacyWS, func(conn *wsConn, received chan<- wsMessage) {
+		expectMessage(t, conn, received, "connection_init")
+		send(t, conn, `{"type": "connection_ack"}`)
+		send(t, conn, `{"type": "ka"}`)
+
+		expectMessage(t, conn, received, "start")
+		send(t, conn, `{"id": "1", "type": "data", "payload": {"data": {"title": "a"}}}`)
+		send(t, conn, `{"type": "ka"}`)
+		send(t, conn, `{"id": "1", "type": "data", "payload": {"data": {"title": "b"}}}`)
+		send(t, conn, `{"id": "1", "type": "complete"}`)
+	})
+	defer server.Close()
+
+	events, err := collect(t, server)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"title": "a"}`, `{"title": "b"}`}, events)
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	server, _ := newWSServer(t, ProtocolTransportWS, func(conn *wsConn, received chan<- wsMessage) {
+		expectMessage(t, conn, received, "connection_init")
+		send(t, conn, `{"type": "connection_ack"}`)
+		expectMessage(t, conn, received, "subscribe")
+		send(t, conn, `{"id": "1", "type": "error", "payload": [{"message": "Unknown field"}]}`)
+	})
+	defer server.Close()
+
+	_, err := collect(t, server)
+	assert.EqualError(t, err, "Subscription failed: Unknown field")
+
+	server, _ = newWSServer(t, ProtocolLegacyWS, func(conn *wsConn, received chan<- wsMessage) {
+		expectMessage(t, conn, received, "connection_init")
+		send(t, conn, `{"type": "connection_error", "payload": {"message": "Unauthorized"}}`)
+	})
+	defer server.Close()
+
+	_, err = collect(t, server)
+	assert.EqualError(t, err, "Subscription failed: Unauthorized")
+}
+
+func TestSubscribeCancel(t *testing.T) {
+	served := make(chan struct{})
+
+	server, received := newWSServer(t, "", func(conn *wsConn, received chan<- wsMessage) {
+		defer close(served)
+
+		expectMessage(t, conn, received, "connection_init")
+		send(t, conn, `{"type": "connection_ack"}`)
+		expectMessage(t, conn, received, "subscribe")
+		send(t, conn, `{"id": "1", "type": "next", "payload": {"data": {"title": "a"}}}`)
+
+		// The client stops the subscription after the first event
+		expectMessage(t, conn, received, "complete")
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	header := http.Header{"Authorization": {"Bearer token"}}
+	err := NewSubscriber(server.URL, header).Subscribe(ctx, "subscription { a }", nil, func(resp *Response) error {
+		cancel()
+		return nil
+	})
+	assert.NoError(t, err)
+
+	<-served
+	var types []string
+	for len(received) > 0 {
+		types = append(types, (<-received).Type)
+	}
+	assert.Equal(t, []string{"connection_init", "subscribe", "complete"}, types)
+}
+
+func TestWebSocketLargeMessages(t *testing.T) {
+	large := strings.Repeat("x", 70000)
+
+	server, _ := newWSServer(t, ProtocolTransportWS, func(conn *wsConn, received chan<- wsMessage) {
+		expectMessage(t, conn, received, "connection_init")
+		send(t, conn, `{"type": "connection_ack"}`)
+		subscribe := expectMessage(t, conn, received, "subscribe")
+
+		var payload requestBody
+		require.NoError(t, json.Unmarshal(subscribe.Payload, &payload))
+		assert.Equal(t, large, payload.Query)
+
+		data, err := json.Marshal(map[string]interface{}{"id": "1", "type": "next", "payload": map[string]interface{}{"data": large[:300]}})
+		require.NoError(t, err)
+		send(t, conn, string(data))
+	})
+	defer server.Close()
+
+	errStop := errors.New("stop")
+	var event string
+	err := NewSubscriber(server.URL, http.Header{"Authorization": {"Bearer token"}}).Subscribe(context.Background(), large, nil, func(resp *Response) error {
+		event = string(resp.Data)
+		return errStop
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, fmt.Sprintf("%q", large[:300]), event)
+}
+
+func TestSubscribeCancelHandshake(t *testing.T) {
+	// A server that accepts connections but never completes the handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	err = NewSubscriber("ws://"+listener.Addr().String(), nil).Subscribe(ctx, "subscription { a }", nil, func(resp *Response) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Deadlines from the context also bound the handshake
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = wsClient{endpoint: "ws://" + listener.Addr().String()}.dial(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestSubscribeProxy(t *testing.T) {
+	server, _ := newWSServer(t, ProtocolTransportWS, func(conn *wsConn, received chan<- wsMessage) {
+		expectMessage(t, conn, received, "connection_init")
+		send(t, conn, `{"type": "connection_ack"}`)
+		expectMessage(t, conn, received, "subscribe")
+		send(t, conn, `{"id": "1", "type": "next", "payload": {"data": {"title": "a"}}}`)
+		send(t, conn, `{"id": "1", "type": "complete"}`)
+	})
+	defer server.Close()
+
+	var tunneled []string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "CONNECT", r.Method)
+		assert.Equal(t, "Basic dXNlcjpzZWNyZXQ=", r.Header.Get("Proxy-Authorization"))
+		tunneled = append(tunneled, r.Host)
+
+		target, err := net.Dial("tcp", r.Host)
+		require.NoError(t, err)
+		defer target.Close()
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		fmt.Fprint(rw, "HTTP/1.1 200 Connection established\r\n\r\n")
+		rw.Flush()
+
+		go io.Copy(target, rw)
+		io.Copy(conn, target)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	subscriber := wsClient{
+		endpoint: server.URL,
+		header:   http.Header{"Authorization": {"Bearer token"}},
+		proxy:    http.ProxyURL(proxyURL),
+	}
+
+	var events []string
+	err = subscriber.Subscribe(context.Background(), "subscription { a }", nil, func(resp *Response) error {
+		events = append(events, string(resp.Data))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"title": "a"}`}, events)
+	assert.Equal(t, []string{strings.TrimPrefix(server.URL, "http://")}, tunneled)
+}
+
+func TestWebSocketClose(t *testing.T) {
+	closed := make(chan error, 1)
+
+	server, _ := newWSServer(t, ProtocolTransportWS, func(conn *wsConn, received chan<- wsMessage) {
+		_, err := readWSMessage(conn)
+		closed <- err
+	})
+	defer server.Close()
+
+	conn, err := wsClient{endpoint: server.URL, header: http.Header{"Authorization": {"Bearer token"}}}.dial(context.Background())
+	require.NoError(t, err)
+
+	// Closing sends a close message once, and closing again is harmless
+	assert.NoError(t, conn.Close())
+	assert.NoError(t, conn.Close())
+	assert.True(t, websocket.IsCloseError(<-closed, websocket.CloseNormalClosure))
+}
@@ -0,0 +1,104 @@
+package introspection
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jclem/graphsh/graphql"
+)
+
+// StaleSchemaError is returned when introspection fails but an expired cached
+// schema could be loaded instead
+type StaleSchemaError struct {
+	Err error
+}
+
+func (e *StaleSchemaError) Error() string {
+	return fmt.Sprintf("Using an expired cached schema, since introspection failed: %s", e.Err)
+}
+
+// LoadCachedSchema loads the schema from a cache file if the file is younger
+// than ttl, and otherwise introspects the schema and updates the cache file
+//
+// If introspection fails and an expired cache file exists, the expired schema
+// is returned along with a *StaleSchemaError.
+func LoadCachedSchema(q graphql.Querier, path string, ttl time.Duration) (*Schema, error) {
+	cached, modTime, cacheErr := readSchemaCache(path)
+	if cacheErr == nil && time.Since(modTime) < ttl {
+		return cached, nil
+	}
+
+	schema, err := RefreshSchema(q, path)
+	if err != nil {
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		return cached, &StaleSchemaError{Err: err}
+	}
+
+	return schema, nil
+}
+
+// RefreshSchema introspects the schema and writes it to a cache file unless
+// the path is empty
+func RefreshSchema(q graphql.Querier, path string) (*Schema, error) {
+	schema, data, err := fetchSchema(q)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return schema, nil
+	}
+
+	return schema, writeSchemaCache(path, data)
+}
+
+func readSchemaCache(path string) (*Schema, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	cached, err := ParseSchema(data)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return cached, info.ModTime(), nil
+}
+
+// writeSchemaCache writes an introspection result to a cache file, replacing
+// the file at once so that a partially written cache is never read
+func writeSchemaCache(path string, data []byte) error {
+	// The schema of a private API may itself be private
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".schema-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,74 @@
+package introspection
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jclem/graphsh/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingQuerier counts the schema queries made through it, leaving out the
+// queries that probe the server's capabilities
+type countingQuerier struct {
+	graphql.Querier
+	count int
+}
+
+func (q *countingQuerier) Query(query string, variables map[string]interface{}) (*graphql.Response, error) {
+	if strings.Contains(query, "__schema") {
+		q.count++
+	}
+
+	return q.Querier.Query(query, variables)
+}
+
+type failingQuerier struct{}
+
+func (failingQuerier) Query(query string, variables map[string]interface{}) (*graphql.Response, error) {
+	return nil, errors.New("offline")
+}
+
+func TestLoadCachedSchema(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphsh")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "schemas", "endpoint.json")
+	q := &countingQuerier{Querier: fileQuerier("testdata/schema.json")}
+
+	// A missing cache is filled by introspecting
+	schema, err := LoadCachedSchema(q, path, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.count)
+	assert.Equal(t, "Query", schema.QueryType.Name)
+	assert.FileExists(t, path)
+
+	// A fresh cache is used without introspecting
+	schema, err = LoadCachedSchema(q, path, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.count)
+	assert.Equal(t, "Query", schema.QueryType.Name)
+
+	// An expired cache is revalidated
+	_, err = LoadCachedSchema(q, path, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, q.count)
+
+	// An expired cache is used if introspection fails
+	schema, err = LoadCachedSchema(failingQuerier{}, path, 0)
+	assert.EqualError(t, err, "Using an expired cached schema, since introspection failed: offline")
+	assert.IsType(t, &StaleSchemaError{}, err)
+	assert.Equal(t, "Query", schema.QueryType.Name)
+
+	// Without a cache, introspection failures are returned as-is
+	schema, err = LoadCachedSchema(failingQuerier{}, filepath.Join(dir, "missing.json"), time.Hour)
+	assert.EqualError(t, err, "offline")
+	assert.Nil(t, schema)
+}
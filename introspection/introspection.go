@@ -1,62 +1,359 @@
 package introspection
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 
 	"github.com/jclem/graphsh/graphql"
 	"github.com/jclem/graphsh/querybuilder"
 )
 
-var schema *Schema
-
 // GetFields gets the fields for a given query
-func GetFields(q graphql.Querier, query *querybuilder.Query) ([]Field, error) {
-	typ, ok := schema.GetQueryType()
-	if !ok {
-		return nil, errors.New("No QueryType present in schema")
+func (s *Schema) GetFields(query *querybuilder.Query) ([]Field, error) {
+	typ, err := s.GetType(query)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, node := range query.List() {
-		if node.ConcreteType == "" {
-			field, ok := typ.GetField(node.Name)
-			if !ok {
-				return nil, fmt.Errorf("Missing field %q from type %q", node.Name, typ.Name)
+	return typ.Fields, nil
+}
+
+// GetType gets the type of the lowest node of a given query, taking its
+// concrete type into account
+func (s *Schema) GetType(query *querybuilder.Query) (*FullType, error) {
+	_, typ, err := s.getType(query, true)
+	return typ, err
+}
+
+// GetNodeType gets the type of the lowest node of a given query, ignoring any
+// concrete type applied to it
+func (s *Schema) GetNodeType(query *querybuilder.Query) (*FullType, error) {
+	_, typ, err := s.getType(query, false)
+	return typ, err
+}
+
+// GetNodeField gets the field of the lowest node of a given query, which is
+// nil for the root node
+func (s *Schema) GetNodeField(query *querybuilder.Query) (*Field, error) {
+	field, _, err := s.getType(query, false)
+	return field, err
+}
+
+// GetPossibleTypes gets the names of the types that can be applied to the
+// lowest node of a given query, which are the possible types of an interface
+// or union, or the interfaces an object implements
+func (s *Schema) GetPossibleTypes(query *querybuilder.Query) ([]string, error) {
+	typ, err := s.GetNodeType(query)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := typ.PossibleTypes
+	if typ.Kind == "OBJECT" {
+		refs = typ.Interfaces
+	}
+
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+
+	return names, nil
+}
+
+// ConcreteField is a field that is only available after applying one of the
+// concrete types that define it
+type ConcreteField struct {
+	Field
+	Types []string
+}
+
+// GetConcreteFields gets the fields of the possible types of the lowest node
+// of a given query that its interface or union type does not have
+func (s *Schema) GetConcreteFields(query *querybuilder.Query) ([]ConcreteField, error) {
+	typ, err := s.GetType(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ.Kind != "INTERFACE" && typ.Kind != "UNION" {
+		return nil, nil
+	}
+
+	var fields []ConcreteField
+	indexes := map[string]int{}
+
+	for _, ref := range typ.PossibleTypes {
+		possibleType, ok := s.LookupType(ref.Name)
+		if !ok {
+			return nil, fmt.Errorf("Missing type %q", ref.Name)
+		}
+
+		for _, field := range possibleType.Fields {
+			if _, ok := typ.GetField(field.Name); ok {
+				continue
 			}
 
-			typ, ok = schema.GetType(field.GetTypeName())
-			if !ok {
-				return nil, fmt.Errorf("Missing type %q", field.GetTypeName())
+			if i, ok := indexes[field.Name]; ok {
+				fields[i].Types = append(fields[i].Types, possibleType.Name)
+				continue
 			}
-		} else {
-			typ, ok = schema.GetType(node.ConcreteType)
+
+			indexes[field.Name] = len(fields)
+			fields = append(fields, ConcreteField{Field: field, Types: []string{possibleType.Name}})
+		}
+	}
+
+	return fields, nil
+}
+
+// getType gets the field and type of the lowest node of a given query, with a
+// nil field for the root node
+func (s *Schema) getType(query *querybuilder.Query, applyLastConcreteType bool) (*Field, *FullType, error) {
+	typ, err := s.GetRootType(query.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var field *Field
+	nodes := query.List()
+
+	for i, node := range nodes {
+		var ok bool
+
+		field, ok = typ.GetField(node.Name)
+		if !ok {
+			return nil, nil, fmt.Errorf("Missing field %q from type %q", node.Name, typ.Name)
+		}
+
+		typ, ok = s.LookupType(field.GetTypeName())
+		if !ok {
+			return nil, nil, fmt.Errorf("Missing type %q", field.GetTypeName())
+		}
+
+		if node.ConcreteType != "" && (applyLastConcreteType || i < len(nodes)-1) {
+			typ, ok = s.LookupType(node.ConcreteType)
 			if !ok {
-				return nil, fmt.Errorf("Missing type %q", node.ConcreteType)
+				return nil, nil, fmt.Errorf("Missing type %q", node.ConcreteType)
 			}
 		}
 	}
 
-	return typ.Fields, nil
+	return field, typ, nil
+}
+
+// LoadSchema introspects the schema
+func LoadSchema(q graphql.Querier) (*Schema, error) {
+	schema, _, err := fetchSchema(q)
+	return schema, err
+}
+
+// fetchSchema introspects the schema, returning it along with the raw
+// introspection result
+//
+// The server is asked which optional introspection fields it supports first,
+// and the schema is introspected again without them if it rejects them.
+func fetchSchema(q graphql.Querier) (*Schema, json.RawMessage, error) {
+	c, err := probeCapabilities(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result introspection
+
+	data, err := queryIntrospection(q, buildSchemaQuery(c, typeRefDepth), &result)
+	if _, ok := err.(*rejectedError); ok && c != (capabilities{}) {
+		c = capabilities{}
+		data, err = queryIntrospection(q, buildSchemaQuery(c, typeRefDepth), &result)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema := &result.Schema
+
+	resolved, err := resolveDeepTypes(q, c, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The raw result no longer matches the schema once deep types are resolved
+	if resolved {
+		if data, err = json.Marshal(result); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	schema.index()
+
+	return schema, data, nil
+}
+
+// rejectedError is returned when a server responds to an introspection query
+// with errors instead of data
+type rejectedError struct {
+	message string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("Unable to load schema: %s", e.message)
+}
+
+// queryIntrospection runs an introspection query, decoding its result into v
+// and returning the raw result
+func queryIntrospection(q graphql.Querier, query string, v interface{}) (json.RawMessage, error) {
+	resp, err := q.Query(query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.HasData() && len(resp.Errors) > 0 {
+		return nil, &rejectedError{message: resp.Errors[0].Message}
+	}
+
+	return resp.Data, json.Unmarshal(resp.Data, v)
 }
 
-// LoadSchema pre-loads the schema struct
-func LoadSchema(q graphql.Querier) error {
-	if schema != nil {
-		return nil
+// probeCapabilities finds which optional introspection fields a server
+// supports, assuming none if the server rejects the question
+func probeCapabilities(q graphql.Querier) (capabilities, error) {
+	var result struct {
+		Schema     *metaType
+		Type       *metaType
+		Field      *metaType
+		Directive  *metaType
+		InputValue *metaType
+	}
+
+	if _, err := queryIntrospection(q, capabilitiesQuery, &result); err != nil {
+		if _, ok := err.(*rejectedError); ok {
+			return capabilities{}, nil
+		}
+
+		return capabilities{}, err
 	}
 
-	respBody, err := q.Query(schemaQuery)
+	return capabilities{
+		schemaDescription:       result.Schema.hasField("description"),
+		specifiedByURL:          result.Type.hasField("specifiedByURL"),
+		isRepeatable:            result.Directive.hasField("isRepeatable"),
+		inputValueDeprecation:   result.InputValue.hasField("isDeprecated") && result.InputValue.hasField("deprecationReason"),
+		deprecatedArgs:          result.Field.hasArg("args", "includeDeprecated"),
+		deprecatedInputFields:   result.Type.hasArg("inputFields", "includeDeprecated"),
+		deprecatedDirectiveArgs: result.Directive.hasArg("args", "includeDeprecated"),
+	}, nil
+}
+
+// resolveDeepTypes introspects the types and directives with type references
+// nested deeper than the schema query asked for, asking for twice as many
+// wrapping types each time, and returns whether there were any
+func resolveDeepTypes(q graphql.Querier, c capabilities, schema *Schema) (bool, error) {
+	resolved := false
+
+	for depth := typeRefDepth; ; depth *= 2 {
+		var indexes []int
+		var names []string
+
+		for i := range schema.Types {
+			if schema.Types[i].isTruncated(depth) {
+				indexes = append(indexes, i)
+				names = append(names, schema.Types[i].Name)
+			}
+		}
+
+		withDirectives := false
+		for i := range schema.Directives {
+			if schema.Directives[i].isTruncated(depth) {
+				withDirectives = true
+			}
+		}
+
+		if len(names) == 0 && !withDirectives {
+			return resolved, nil
+		}
+
+		var result map[string]json.RawMessage
+		if _, err := queryIntrospection(q, buildDeepQuery(c, depth*2, names, withDirectives), &result); err != nil {
+			return false, err
+		}
+
+		for i, index := range indexes {
+			var typ FullType
+			if raw, ok := result[fmt.Sprintf("type%d", i)]; ok {
+				if err := json.Unmarshal(raw, &typ); err != nil {
+					return false, err
+				}
+			}
+
+			if typ.Name != names[i] {
+				return false, fmt.Errorf("Missing type %q", names[i])
+			}
+
+			schema.Types[index] = typ
+		}
+
+		if withDirectives {
+			var directives struct {
+				Directives []Directive
+			}
+
+			if err := json.Unmarshal(result["__schema"], &directives); err != nil {
+				return false, err
+			}
+
+			schema.Directives = directives.Directives
+		}
+
+		resolved = true
+	}
+}
+
+// LoadSchemaFile loads the schema from a file instead of introspecting it,
+// which may be an introspection result or a schema definition language
+// document
+func LoadSchemaFile(path string) (*Schema, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := ParseSchema(data)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Unable to load schema from %s: %s", path, err)
+	}
+
+	return schema, nil
+}
+
+// ParseSchema parses an introspection result, with or without its "data"
+// wrapper, or else a schema definition language document
+func ParseSchema(data []byte) (*Schema, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return ParseSDL(string(data))
+	}
+
+	var result struct {
+		Data *introspection
+		introspection
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Data != nil {
+		result.introspection = *result.Data
 	}
 
-	var introspection introspection
-	if err := json.Unmarshal(respBody, &introspection); err != nil {
-		return err
+	if result.Schema.QueryType.Name == "" {
+		return nil, errors.New("Expected an introspection result with a \"__schema\" field")
 	}
 
-	schema = &introspection.Data.Schema
+	result.Schema.index()
 
-	return nil
+	return &result.Schema, nil
 }
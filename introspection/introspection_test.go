@@ -0,0 +1,176 @@
+package introspection
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileQuerier string
+
+func (f fileQuerier) Query(query string, variables map[string]interface{}) (*graphql.Response, error) {
+	body, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return nil, err
+	}
+
+	return graphql.ParseResponse(body)
+}
+
+func loadTestSchema(t *testing.T) *Schema {
+	schema, err := LoadSchema(fileQuerier("testdata/schema.json"))
+	require.NoError(t, err)
+
+	return schema
+}
+
+func mustParsePath(t *testing.T, path string) *querybuilder.Query {
+	root := querybuilder.NewRootQuery()
+
+	queries, err := querybuilder.ParsePath(path)
+	require.NoError(t, err)
+	root.AddChild(queries[0])
+
+	return root
+}
+
+func TestGetType(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	typ, err := schema.GetType(mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").owner`))
+	assert.NoError(t, err)
+	assert.Equal(t, "RepositoryOwner", typ.Name)
+
+	query := mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").object`)
+	query.Child().Child().ConcreteType = "Commit"
+	typ, err = schema.GetType(query)
+	assert.NoError(t, err)
+	assert.Equal(t, "Commit", typ.Name)
+
+	_, err = schema.GetType(mustParsePath(t, ".nope"))
+	assert.EqualError(t, err, `Missing field "nope" from type "Query"`)
+}
+
+func TestGetNodeField(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	field, err := schema.GetNodeField(querybuilder.NewRootQuery())
+	assert.NoError(t, err)
+	assert.Nil(t, field)
+
+	field, err = schema.GetNodeField(mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").issues`))
+	require.NoError(t, err)
+	assert.Equal(t, "issues", field.Name)
+
+	var args []string
+	for _, arg := range field.Args {
+		args = append(args, fmt.Sprintf("%s %s %v", arg.Name, arg.GetHumanTypeName(), arg.IsRequired()))
+	}
+	assert.Equal(t, []string{
+		"states Enum<[]IssueState> false",
+		"orderBy InputObject<IssueOrder> false",
+		"labels {[]String} false",
+		"first {Int} false",
+	}, args)
+
+	query, err := schema.GetType(querybuilder.NewRootQuery())
+	require.NoError(t, err)
+	search, _ := query.GetField("search")
+	assert.True(t, search.Args[0].IsRequired())
+	assert.False(t, search.Args[2].IsRequired(), "Arguments with defaults are not required")
+}
+
+func TestGetPossibleTypes(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	names, err := schema.GetPossibleTypes(mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").object`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Blob", "Commit"}, names)
+
+	names, err = schema.GetPossibleTypes(mustParsePath(t, ".viewer"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Node", "RepositoryOwner"}, names)
+
+	names, err = schema.GetPossibleTypes(mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").issues`))
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestGetConcreteFields(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	query := mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").owner`)
+
+	fields, err := schema.GetConcreteFields(query)
+	require.NoError(t, err)
+
+	var names []string
+	for _, field := range fields {
+		names = append(names, fmt.Sprintf("%s %v", field.Name, field.Types))
+	}
+	assert.Equal(t, []string{"id [Organization User]", "membersCount [Organization]", "name [User]", "bio [User]"}, names)
+
+	query.Child().Child().ConcreteType = "User"
+	fields, err = schema.GetConcreteFields(query)
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestGetVariableDefinitions(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	query := mustParsePath(t, ".repository(owner: $owner, name: $name).issues(states: [$state], orderBy: {field: CREATED_AT, direction: $direction})")
+	selections, err := querybuilder.ParseSelections("nodes @include(if: $withNodes) { title, author { repositories(first: $first) { totalCount } } }")
+	require.NoError(t, err)
+
+	defs, err := schema.GetVariableDefinitions(query, selections)
+	assert.NoError(t, err)
+	assert.Equal(t, []querybuilder.VariableDefinition{
+		{Name: "direction", Type: "OrderDirection!"},
+		{Name: "first", Type: "Int"},
+		{Name: "name", Type: "String!"},
+		{Name: "owner", Type: "String!"},
+		{Name: "state", Type: "IssueState!"},
+		{Name: "withNodes", Type: "Boolean!"},
+	}, defs)
+
+	// Variables of introspection meta-fields are inferred too
+	selections, err = querybuilder.ParseSelections("__type(name: $typeName) { fields(includeDeprecated: $deprecated) { name } }")
+	require.NoError(t, err)
+
+	defs, err = schema.GetVariableDefinitions(querybuilder.NewRootQuery(), selections)
+	assert.NoError(t, err)
+	assert.Equal(t, []querybuilder.VariableDefinition{
+		{Name: "deprecated", Type: "Boolean"},
+		{Name: "typeName", Type: "String!"},
+	}, defs)
+}
+
+func TestGetVariableDefinitionsErrors(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	_, err := schema.GetVariableDefinitions(mustParsePath(t, ".repository(owner: $x, name: \"graphsh\").issues(first: $x)"), nil)
+	assert.EqualError(t, err, "Variable $x is used as both String! and Int")
+
+	selections, err := querybuilder.ParseSelections("nope(arg: $x)")
+	require.NoError(t, err)
+	_, err = schema.GetVariableDefinitions(mustParsePath(t, ".viewer"), selections)
+	assert.EqualError(t, err, "Can not infer the type of variable $x")
+}
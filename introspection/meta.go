@@ -0,0 +1,133 @@
+package introspection
+
+import "strings"
+
+// metaSDL defines the __schema and __type meta-fields of the query root type
+// and the introspection types they return, which introspection results and
+// SDL documents may omit
+const metaSDL = `
+type Query {
+  __schema: __Schema!
+  __type(name: String!): __Type
+}
+
+type __Schema {
+  description: String
+  types: [__Type!]!
+  queryType: __Type!
+  mutationType: __Type
+  subscriptionType: __Type
+  directives: [__Directive!]!
+}
+
+type __Type {
+  kind: __TypeKind!
+  name: String
+  description: String
+  specifiedByURL: String
+  fields(includeDeprecated: Boolean = false): [__Field!]
+  interfaces: [__Type!]
+  possibleTypes: [__Type!]
+  enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
+  inputFields(includeDeprecated: Boolean = false): [__InputValue!]
+  ofType: __Type
+}
+
+enum __TypeKind {
+  SCALAR
+  OBJECT
+  INTERFACE
+  UNION
+  ENUM
+  INPUT_OBJECT
+  LIST
+  NON_NULL
+}
+
+type __Field {
+  name: String!
+  description: String
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  type: __Type!
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __InputValue {
+  name: String!
+  description: String
+  type: __Type!
+  defaultValue: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __EnumValue {
+  name: String!
+  description: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+type __Directive {
+  name: String!
+  description: String
+  locations: [__DirectiveLocation!]!
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  isRepeatable: Boolean!
+}
+
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  VARIABLE_DEFINITION
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
+`
+
+// metaSchema is the schema defined by metaSDL
+var metaSchema = mustParseSDL(metaSDL)
+
+func mustParseSDL(sdl string) *Schema {
+	schema, err := ParseSDL(sdl)
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+// metaField gets the __schema or __type meta-field of the query root type
+func (s *Schema) metaField(typ *FullType, name string) (*Field, bool) {
+	if typ.Name != s.QueryType.Name {
+		return nil, false
+	}
+
+	query, _ := metaSchema.GetQueryType()
+	return query.GetField(name)
+}
+
+// lookupTypeOrMeta looks up a type by name, falling back to the introspection
+// types for schemas that omit them
+func (s *Schema) lookupTypeOrMeta(name string) (*FullType, bool) {
+	if typ, ok := s.LookupType(name); ok || !strings.HasPrefix(name, "__") {
+		return typ, ok
+	}
+
+	return metaSchema.LookupType(name)
+}
@@ -0,0 +1,230 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PathStep is a step of a path through the schema, which traverses into a
+// field and may then apply a concrete type to it
+//
+// Only the first step of a path may have no field, in which case it applies a
+// concrete type to the node the path starts from.
+type PathStep struct {
+	Field        string
+	Args         []PathArg
+	ConcreteType string
+}
+
+// PathArg is a required argument of a path step, given as a variable
+type PathArg struct {
+	Name     string
+	Variable string
+}
+
+// Path is a path through the schema from one type to another
+type Path []PathStep
+
+// Commands converts a path to the graphsh commands that traverse it, which
+// are path traversals and `on` commands, such as `.node(id: $id)` followed by
+// `on PullRequest`
+func (p Path) Commands() []string {
+	var commands []string
+	var traversal strings.Builder
+
+	for _, step := range p {
+		if step.Field != "" {
+			traversal.WriteString("." + step.Field)
+
+			if len(step.Args) > 0 {
+				args := make([]string, len(step.Args))
+				for i, arg := range step.Args {
+					args[i] = fmt.Sprintf("%s: $%s", arg.Name, arg.Variable)
+				}
+
+				fmt.Fprintf(&traversal, "(%s)", strings.Join(args, ", "))
+			}
+		}
+
+		if step.ConcreteType != "" {
+			if traversal.Len() > 0 {
+				commands = append(commands, traversal.String())
+				traversal.Reset()
+			}
+
+			commands = append(commands, "on "+step.ConcreteType)
+		}
+	}
+
+	if traversal.Len() > 0 {
+		commands = append(commands, traversal.String())
+	}
+
+	return commands
+}
+
+// pathNode is a type reached by a search for paths, along with the step that
+// reached it
+type pathNode struct {
+	typ  string
+	step PathStep
+	prev *pathNode
+}
+
+// PathsTo finds up to limit of the shortest paths from a type to another,
+// shortest first
+//
+// Paths traverse into non-deprecated fields and apply concrete types to
+// interfaces and unions, and never pass through the same type twice. Required
+// arguments are given as variables named after them.
+func (s *Schema) PathsTo(from string, target string, limit int) ([]Path, error) {
+	if _, err := s.compositeType(from); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.compositeType(target); err != nil {
+		return nil, err
+	}
+
+	var paths []Path
+
+	// Each type is queued at most limit times, since a type's first limit
+	// arrivals are its shortest, and the search stops as soon as it has found
+	// limit paths
+	root := &pathNode{typ: from}
+	visits := map[string]int{from: 1}
+	queue := []*pathNode{root}
+
+	add := func(node *pathNode) {
+		if node.typ == target {
+			paths = append(paths, node.path())
+		} else if visits[node.typ] < limit {
+			visits[node.typ]++
+			queue = append(queue, node)
+		}
+	}
+
+	// Concrete types are applied to interfaces and unions as soon as they are
+	// reached, so that they are searched in the same layer as the fields that
+	// reached them. They can only be applied once to each node.
+	addConcreteTypes := func(node *pathNode) {
+		typ, ok := s.LookupType(node.typ)
+		if !ok || typ.Kind == "OBJECT" {
+			return
+		}
+
+		prev, step := node.prev, node.step
+		if prev == nil {
+			prev, step = node, PathStep{}
+		}
+
+		for _, possible := range typ.PossibleTypes {
+			if !node.contains(possible.Name) {
+				step.ConcreteType = possible.Name
+				add(&pathNode{typ: possible.Name, step: step, prev: prev})
+			}
+		}
+	}
+
+	addConcreteTypes(root)
+
+	for len(queue) > 0 && len(paths) < limit {
+		node := queue[0]
+		queue = queue[1:]
+
+		typ, ok := s.LookupType(node.typ)
+		if !ok {
+			continue
+		}
+
+		for _, field := range typ.Fields {
+			if field.IsDeprecated || node.contains(field.GetTypeName()) {
+				continue
+			}
+
+			if fieldType, ok := s.LookupType(field.GetTypeName()); !ok || !isCompositeType(fieldType) {
+				continue
+			}
+
+			step := PathStep{Field: field.Name}
+			for _, arg := range field.Args {
+				if arg.IsRequired() {
+					step.Args = append(step.Args, PathArg{Name: arg.Name})
+				}
+			}
+
+			child := &pathNode{typ: field.GetTypeName(), step: step, prev: node}
+			add(child)
+
+			if child.typ != target {
+				addConcreteTypes(child)
+			}
+		}
+	}
+
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+
+	return paths, nil
+}
+
+// compositeType looks up an object, interface, or union type by name
+func (s *Schema) compositeType(name string) (*FullType, error) {
+	typ, ok := s.LookupType(name)
+	if !ok {
+		var names []string
+		for i := range s.Types {
+			if isCompositeType(&s.Types[i]) {
+				names = append(names, s.Types[i].Name)
+			}
+		}
+
+		return nil, fmt.Errorf("Unknown type %q%s", name, didYouMean(name, names))
+	}
+
+	if !isCompositeType(typ) {
+		return nil, fmt.Errorf("Type %q is not an object, interface, or union type", name)
+	}
+
+	return typ, nil
+}
+
+// contains is whether a type has been reached on the way to this node
+func (n *pathNode) contains(typ string) bool {
+	for ; n != nil; n = n.prev {
+		if n.typ == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// path gets the steps that reached this node, naming the variables of their
+// arguments uniquely
+func (n *pathNode) path() Path {
+	var path Path
+	for ; n.prev != nil; n = n.prev {
+		path = append(Path{n.step}, path...)
+	}
+
+	used := map[string]int{}
+
+	for i, step := range path {
+		args := make([]PathArg, len(step.Args))
+
+		for j, arg := range step.Args {
+			used[arg.Name]++
+
+			args[j] = PathArg{Name: arg.Name, Variable: arg.Name}
+			if used[arg.Name] > 1 {
+				args[j].Variable = fmt.Sprintf("%s%d", arg.Name, used[arg.Name])
+			}
+		}
+
+		path[i].Args = args
+	}
+
+	return path
+}
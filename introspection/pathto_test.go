@@ -0,0 +1,105 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pathStrings(t *testing.T, schema *Schema, from string, target string, limit int) []string {
+	paths, err := schema.PathsTo(from, target, limit)
+	require.NoError(t, err)
+
+	var printed []string
+	for _, path := range paths {
+		printed = append(printed, strings.Join(path.Commands(), "; "))
+	}
+
+	return printed
+}
+
+func TestPathsTo(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	// Shortest paths come first, with concrete types applied to interfaces and
+	// unions, and required arguments as variables
+	assert.Equal(t, []string{
+		".node(id: $id); on Commit",
+		".repository(owner: $owner, name: $name).object; on Commit",
+		".node(id: $id); on Repository; .object; on Commit",
+	}, pathStrings(t, schema, "Query", "Commit", 3))
+
+	assert.Equal(t, []string{".search(query: $query, type: $type).nodes"}, pathStrings(t, schema, "Query", "SearchResultItem", 10))
+
+	// Concrete types can be applied to the starting type
+	assert.Equal(t, []string{"on Commit", "on Repository; .object; on Commit"}, pathStrings(t, schema, "Node", "Commit", 2))
+
+	paths, err := schema.PathsTo("Query", "Commit", 1)
+	require.NoError(t, err)
+	assert.Equal(t, Path{
+		{Field: "node", Args: []PathArg{{Name: "id", Variable: "id"}}, ConcreteType: "Commit"},
+	}, paths[0])
+
+	_, err = schema.PathsTo("Query", "Comit", 1)
+	assert.EqualError(t, err, `Unknown type "Comit", did you mean "Commit"?`)
+
+	_, err = schema.PathsTo("Query", "String", 1)
+	assert.EqualError(t, err, `Type "String" is not an object, interface, or union type`)
+}
+
+func TestPathsToConcreteTypesFirst(t *testing.T) {
+	t.Parallel()
+
+	// Fields of a concrete type are searched in the same layer as the field
+	// that reached its union, so .u on X .t is shorter than .a.b.t
+	schema, err := ParseSDL(`
+type Query { a: A u: U }
+type A { b: B }
+type B { t: T }
+union U = X | Y
+type X { t: T }
+type Y { id: ID }
+type T { id: ID }
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{".u; on X; .t"}, pathStrings(t, schema, "Query", "T", 1))
+	assert.Equal(t, []string{".u; on X; .t", ".a.b.t"}, pathStrings(t, schema, "Query", "T", 5))
+}
+
+func TestPathVariables(t *testing.T) {
+	t.Parallel()
+
+	// Variables are numbered when more than one argument has the same name
+	schema, err := ParseSDL(`
+type Query { user(login: String!): User }
+type User { repository(name: String!): Repository, friend(login: String!): User }
+type Repository { owner(login: String!): Owner }
+type Owner { login: String }
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		".user(login: $login).repository(name: $name).owner(login: $login2)",
+	}, pathStrings(t, schema, "Query", "Owner", 10))
+}
+
+func BenchmarkPathsTo(b *testing.B) {
+	schema := largeSchema(b)
+
+	for i := 0; i < b.N; i++ {
+		paths, err := schema.PathsTo("Query", fmt.Sprintf("Type%d", largeSchemaTypes-1), 10)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		if len(paths) != 10 {
+			b.Fatalf("Expected 10 paths, got %d", len(paths))
+		}
+	}
+}
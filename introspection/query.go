@@ -0,0 +1,234 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jclem/graphsh/querybuilder"
+)
+
+// typeRefDepth is how many wrapping types of a type reference the schema
+// query asks for, which is enough for all but the most deeply nested lists
+const typeRefDepth = 9
+
+// capabilitiesQuery asks for the fields of the introspection types, to find
+// which optional introspection fields the server supports
+const capabilitiesQuery = `
+query IntrospectionCapabilities {
+  schema: __type(name: "__Schema") {
+    ...Capabilities
+  }
+  type: __type(name: "__Type") {
+    ...Capabilities
+  }
+  field: __type(name: "__Field") {
+    ...Capabilities
+  }
+  directive: __type(name: "__Directive") {
+    ...Capabilities
+  }
+  inputValue: __type(name: "__InputValue") {
+    ...Capabilities
+  }
+}
+
+fragment Capabilities on __Type {
+  fields {
+    name
+    args {
+      name
+    }
+  }
+}
+`
+
+// capabilities are the optional introspection fields a server supports,
+// whose zero value is supported by every server
+type capabilities struct {
+	// schemaDescription is whether __Schema has a description
+	schemaDescription bool
+
+	// specifiedByURL is whether __Type has a specifiedByURL
+	specifiedByURL bool
+
+	// isRepeatable is whether __Directive has isRepeatable
+	isRepeatable bool
+
+	// inputValueDeprecation is whether __InputValue has isDeprecated and
+	// deprecationReason
+	inputValueDeprecation bool
+
+	// deprecatedArgs, deprecatedInputFields, and deprecatedDirectiveArgs are
+	// whether the fields listing input values accept includeDeprecated
+	deprecatedArgs          bool
+	deprecatedInputFields   bool
+	deprecatedDirectiveArgs bool
+}
+
+// metaType is an introspection type, as described by the server
+type metaType struct {
+	Fields []struct {
+		Name string
+		Args []struct {
+			Name string
+		}
+	}
+}
+
+func (t *metaType) hasField(name string) bool {
+	return t.hasArg(name, "")
+}
+
+// hasArg is whether the type has a field that accepts the given argument, or
+// just the field if the argument is empty
+func (t *metaType) hasArg(field string, arg string) bool {
+	if t == nil {
+		return false
+	}
+
+	for _, f := range t.Fields {
+		if f.Name != field {
+			continue
+		}
+
+		if arg == "" {
+			return true
+		}
+
+		for _, a := range f.Args {
+			if a.Name == arg {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildSchemaQuery builds an introspection query for the whole schema that
+// asks for the optional fields the server supports
+func buildSchemaQuery(c capabilities, depth int) string {
+	var b strings.Builder
+
+	b.WriteString("query IntrospectionQuery {\n  __schema {\n")
+	if c.schemaDescription {
+		b.WriteString("    description\n")
+	}
+	b.WriteString(`    queryType {
+      name
+    }
+    mutationType {
+      name
+    }
+    subscriptionType {
+      name
+    }
+    types {
+      ...FullType
+    }
+    directives {
+      ...Directive
+    }
+  }
+}
+`)
+
+	writeFragments(&b, c, depth, true, true)
+
+	return b.String()
+}
+
+// buildDeepQuery builds an introspection query for the named types, and the
+// directives if withDirectives is true, which is used to ask for type
+// references nested deeper than the schema query asked for
+func buildDeepQuery(c capabilities, depth int, names []string, withDirectives bool) string {
+	var b strings.Builder
+
+	b.WriteString("query IntrospectionDeepTypes {\n")
+
+	if withDirectives {
+		b.WriteString("  __schema {\n    directives {\n      ...Directive\n    }\n  }\n")
+	}
+
+	for i, name := range names {
+		fmt.Fprintf(&b, "  type%d: __type(name: %s) {\n    ...FullType\n  }\n", i, querybuilder.StringValue(name))
+	}
+
+	b.WriteString("}\n")
+
+	writeFragments(&b, c, depth, len(names) > 0, withDirectives)
+
+	return b.String()
+}
+
+// writeFragments writes the fragments used by an introspection query, since
+// a GraphQL query may not define fragments it does not use
+func writeFragments(b *strings.Builder, c capabilities, depth int, withTypes bool, withDirectives bool) {
+	if withTypes {
+		b.WriteString("\nfragment FullType on __Type {\n  kind\n  name\n  description\n")
+		if c.specifiedByURL {
+			b.WriteString("  specifiedByURL\n")
+		}
+		fmt.Fprintf(b, `  fields(includeDeprecated: true) {
+    name
+    description
+    %s {
+      ...InputValue
+    }
+    type {
+      ...TypeRef
+    }
+    isDeprecated
+    deprecationReason
+  }
+  %s {
+    ...InputValue
+  }
+  interfaces {
+    ...TypeRef
+  }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes {
+    ...TypeRef
+  }
+}
+`, includeDeprecated("args", c.deprecatedArgs), includeDeprecated("inputFields", c.deprecatedInputFields))
+	}
+
+	if withDirectives {
+		b.WriteString("\nfragment Directive on __Directive {\n  name\n  description\n")
+		if c.isRepeatable {
+			b.WriteString("  isRepeatable\n")
+		}
+		fmt.Fprintf(b, "  locations\n  %s {\n    ...InputValue\n  }\n}\n", includeDeprecated("args", c.deprecatedDirectiveArgs))
+	}
+
+	b.WriteString("\nfragment InputValue on __InputValue {\n  name\n  description\n  type {\n    ...TypeRef\n  }\n  defaultValue\n")
+	if c.inputValueDeprecation {
+		b.WriteString("  isDeprecated\n  deprecationReason\n")
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\nfragment TypeRef on __Type {\n  kind\n  name\n")
+	for i := 1; i <= depth; i++ {
+		indent := strings.Repeat("  ", i)
+		fmt.Fprintf(b, "%sofType {\n%s  kind\n%s  name\n", indent, indent, indent)
+	}
+	for i := depth; i >= 1; i-- {
+		fmt.Fprintf(b, "%s}\n", strings.Repeat("  ", i))
+	}
+	b.WriteString("}\n")
+}
+
+func includeDeprecated(field string, supported bool) string {
+	if supported {
+		return field + "(includeDeprecated: true)"
+	}
+
+	return field
+}
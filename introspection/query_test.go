@@ -0,0 +1,210 @@
+package introspection
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jclem/graphsh/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// querierFunc responds to queries with a response body
+type querierFunc func(query string) string
+
+func (f querierFunc) Query(query string, variables map[string]interface{}) (*graphql.Response, error) {
+	return graphql.ParseResponse([]byte(f(query)))
+}
+
+const allCapabilities = `{"data": {
+  "schema": {"fields": [{"name": "description", "args": []}]},
+  "type": {"fields": [{"name": "specifiedByURL", "args": []}, {"name": "inputFields", "args": [{"name": "includeDeprecated"}]}]},
+  "field": {"fields": [{"name": "args", "args": [{"name": "includeDeprecated"}]}]},
+  "directive": {"fields": [{"name": "isRepeatable", "args": []}, {"name": "args", "args": [{"name": "includeDeprecated"}]}]},
+  "inputValue": {"fields": [{"name": "isDeprecated", "args": []}, {"name": "deprecationReason", "args": []}]}
+}}`
+
+const rejected = `{"errors": [{"message": "Cannot query field"}]}`
+
+func respond(t *testing.T, schema Schema) string {
+	body, err := json.Marshal(map[string]interface{}{"data": introspection{Schema: schema}})
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+// listRef wraps a type in depth non-null lists
+func listRef(name string, depth int) TypeRef {
+	ref := TypeRef{Kind: "SCALAR", Name: name}
+	for i := 0; i < depth; i++ {
+		list := TypeRef{Kind: "LIST", OfType: &TypeRef{Kind: ref.Kind, Name: ref.Name, OfType: ref.OfType}}
+		ref = TypeRef{Kind: "NON_NULL", OfType: &list}
+	}
+
+	return ref
+}
+
+// truncate cuts a type reference off after depth wrapping types, as a query
+// of that depth would
+func truncate(ref TypeRef, depth int) TypeRef {
+	if ref.OfType == nil {
+		return ref
+	}
+
+	if depth == 0 {
+		return TypeRef{Kind: ref.Kind, Name: ref.Name}
+	}
+
+	ofType := truncate(*ref.OfType, depth-1)
+	return TypeRef{Kind: ref.Kind, Name: ref.Name, OfType: &ofType}
+}
+
+func testSchema(fieldType TypeRef) Schema {
+	var schema Schema
+	schema.QueryType.Name = "Query"
+	schema.Types = []FullType{
+		{Kind: "OBJECT", Name: "Query", Fields: []Field{{Name: "matrix", Type: fieldType}}},
+		{Kind: "SCALAR", Name: "Int"},
+	}
+
+	return schema
+}
+
+func TestBuildSchemaQuery(t *testing.T) {
+	query := buildSchemaQuery(capabilities{}, typeRefDepth)
+	assert.Equal(t, typeRefDepth, strings.Count(query, "ofType {"))
+	assert.NotContains(t, query, "specifiedByURL")
+	assert.NotContains(t, query, "isRepeatable")
+	assert.NotContains(t, query, "args(includeDeprecated: true)")
+	assert.Contains(t, query, "fields(includeDeprecated: true)")
+
+	query = buildSchemaQuery(capabilities{specifiedByURL: true, isRepeatable: true, deprecatedArgs: true}, 12)
+	assert.Equal(t, 12, strings.Count(query, "ofType {"))
+	assert.Contains(t, query, "  specifiedByURL\n")
+	assert.Contains(t, query, "  isRepeatable\n")
+	assert.Contains(t, query, "    args(includeDeprecated: true) {\n      ...InputValue\n    }\n")
+
+	// Deep queries define only the fragments they use
+	query = buildDeepQuery(capabilities{}, 18, []string{"Query"}, false)
+	assert.Contains(t, query, `type0: __type(name: "Query")`)
+	assert.NotContains(t, query, "fragment Directive")
+}
+
+func TestFetchSchemaCapabilities(t *testing.T) {
+	schema := testSchema(TypeRef{Kind: "SCALAR", Name: "Int"})
+	schema.Description = "A schema"
+	schema.Types = append(schema.Types, FullType{Kind: "SCALAR", Name: "URL", SpecifiedByURL: "https://url.spec.whatwg.org"})
+	schema.Directives = []Directive{{Name: "cached", IsRepeatable: true, Locations: []string{"FIELD"}}}
+
+	var query string
+
+	loaded, err := LoadSchema(querierFunc(func(q string) string {
+		if strings.Contains(q, "IntrospectionCapabilities") {
+			return allCapabilities
+		}
+
+		query = q
+		return respond(t, schema)
+	}))
+	require.NoError(t, err)
+
+	assert.Contains(t, query, "    description\n")
+	assert.Contains(t, query, "  inputFields(includeDeprecated: true) {\n")
+	assert.Contains(t, query, "  isDeprecated\n  deprecationReason\n}\n\nfragment TypeRef")
+
+	assert.Equal(t, `"""A schema"""
+schema {
+  query: Query
+}
+
+directive @cached repeatable on FIELD
+
+type Query {
+  matrix: Int
+}
+
+scalar URL @specifiedBy(url: "https://url.spec.whatwg.org")
+`, PrintSchema(loaded))
+}
+
+func TestFetchSchemaFallback(t *testing.T) {
+	schema := testSchema(TypeRef{Kind: "SCALAR", Name: "Int"})
+
+	// Servers that reject the capabilities query are asked for no optional fields
+	var queries []string
+
+	_, err := LoadSchema(querierFunc(func(q string) string {
+		queries = append(queries, q)
+
+		if strings.Contains(q, "IntrospectionCapabilities") {
+			return rejected
+		}
+
+		return respond(t, schema)
+	}))
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.Equal(t, buildSchemaQuery(capabilities{}, typeRefDepth), queries[1])
+
+	// Servers that reject optional fields they describe are asked again without them
+	queries = nil
+
+	_, err = LoadSchema(querierFunc(func(q string) string {
+		queries = append(queries, q)
+
+		switch {
+		case strings.Contains(q, "IntrospectionCapabilities"):
+			return allCapabilities
+		case strings.Contains(q, "specifiedByURL"):
+			return rejected
+		}
+
+		return respond(t, schema)
+	}))
+	require.NoError(t, err)
+	require.Len(t, queries, 3)
+	assert.Equal(t, buildSchemaQuery(capabilities{}, typeRefDepth), queries[2])
+
+	// Servers that reject the schema query without optional fields fail
+	_, err = LoadSchema(querierFunc(func(q string) string {
+		return rejected
+	}))
+	assert.EqualError(t, err, "Unable to load schema: Cannot query field")
+}
+
+func TestFetchSchemaDeepTypes(t *testing.T) {
+	// Each list adds two wrapping types, so this needs two deeper queries
+	deep := listRef("Int", 12)
+	deepQueries := 0
+
+	schema, data, err := fetchSchema(querierFunc(func(q string) string {
+		switch {
+		case strings.Contains(q, "IntrospectionCapabilities"):
+			return rejected
+		case strings.Contains(q, "IntrospectionDeepTypes"):
+			deepQueries++
+			depth := strings.Count(q, "ofType {")
+
+			body, err := json.Marshal(map[string]interface{}{"data": map[string]interface{}{
+				"type0": testSchema(truncate(deep, depth)).Types[0],
+			}})
+			require.NoError(t, err)
+
+			return string(body)
+		}
+
+		return respond(t, testSchema(truncate(deep, typeRefDepth)))
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, deepQueries)
+
+	query, _ := schema.GetQueryType()
+	matrix, _ := query.GetField("matrix")
+	assert.Equal(t, strings.Repeat("[", 12)+"Int"+strings.Repeat("]!", 12), matrix.Type.String())
+
+	// The raw result includes the deep types, so that it can be cached
+	cached, err := ParseSchema(data)
+	require.NoError(t, err)
+	assert.Equal(t, PrintSchema(schema), PrintSchema(cached))
+}
@@ -0,0 +1,271 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jclem/graphsh/querybuilder"
+)
+
+// defaultDeprecationReason is the reason implied by a bare @deprecated
+const defaultDeprecationReason = "No longer supported"
+
+// builtInDirectives are the directives defined by the GraphQL specification
+var builtInDirectives = []string{"include", "skip", "deprecated", "specifiedBy"}
+
+// builtInScalars are the scalars defined by the GraphQL specification
+var builtInScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// PrintSchema renders a schema as a GraphQL schema definition language
+// document, leaving out the built-in scalars, directives, and introspection
+// types
+func PrintSchema(s *Schema) string {
+	var definitions []string
+
+	if definition := printSchemaDefinition(s); definition != "" {
+		definitions = append(definitions, definition)
+	}
+
+	for _, directive := range s.Directives {
+		if !contains(builtInDirectives, directive.Name) {
+			definitions = append(definitions, PrintDirective(directive))
+		}
+	}
+
+	for i := range s.Types {
+		typ := &s.Types[i]
+
+		if strings.HasPrefix(typ.Name, "__") || (typ.Kind == "SCALAR" && contains(builtInScalars, typ.Name)) {
+			continue
+		}
+
+		definitions = append(definitions, PrintType(typ))
+	}
+
+	return strings.Join(definitions, "\n\n") + "\n"
+}
+
+// printSchemaDefinition renders the schema definition, which is only needed
+// when the schema has a description or the root types do not have their
+// conventional names
+func printSchemaDefinition(s *Schema) string {
+	var roots []string
+	isConventional := s.QueryType.Name == "Query"
+
+	roots = append(roots, fmt.Sprintf("  query: %s", s.QueryType.Name))
+
+	if s.MutationType != nil {
+		isConventional = isConventional && s.MutationType.Name == "Mutation"
+		roots = append(roots, fmt.Sprintf("  mutation: %s", s.MutationType.Name))
+	}
+
+	if s.SubscriptionType != nil {
+		isConventional = isConventional && s.SubscriptionType.Name == "Subscription"
+		roots = append(roots, fmt.Sprintf("  subscription: %s", s.SubscriptionType.Name))
+	}
+
+	if isConventional && s.Description == "" {
+		return ""
+	}
+
+	var b strings.Builder
+
+	printDescription(&b, s.Description, "")
+	b.WriteString("schema {\n" + strings.Join(roots, "\n") + "\n}")
+
+	return b.String()
+}
+
+// PrintDirective renders a directive's definition in the GraphQL schema
+// definition language
+func PrintDirective(directive Directive) string {
+	var b strings.Builder
+
+	printDescription(&b, directive.Description, "")
+	fmt.Fprintf(&b, "directive @%s%s", directive.Name, printArgs(directive.Args, ""))
+	if directive.IsRepeatable {
+		b.WriteString(" repeatable")
+	}
+	fmt.Fprintf(&b, " on %s", strings.Join(directive.Locations, " | "))
+
+	return b.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PrintType renders a type's definition in the GraphQL schema definition
+// language
+func PrintType(typ *FullType) string {
+	var b strings.Builder
+
+	printDescription(&b, typ.Description, "")
+
+	switch typ.Kind {
+	case "SCALAR":
+		fmt.Fprintf(&b, "scalar %s", typ.Name)
+		if typ.SpecifiedByURL != "" {
+			fmt.Fprintf(&b, " @specifiedBy(url: %s)", querybuilder.StringValue(typ.SpecifiedByURL))
+		}
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if typ.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+
+		fmt.Fprintf(&b, "%s %s%s", keyword, typ.Name, printImplements(typ.Interfaces))
+		printBlock(&b, len(typ.Fields), func(i int) {
+			printField(&b, typ.Fields[i])
+		})
+	case "UNION":
+		names := make([]string, 0, len(typ.PossibleTypes))
+		for _, ref := range typ.PossibleTypes {
+			names = append(names, ref.Name)
+		}
+
+		fmt.Fprintf(&b, "union %s", typ.Name)
+		if len(names) > 0 {
+			fmt.Fprintf(&b, " = %s", strings.Join(names, " | "))
+		}
+	case "ENUM":
+		fmt.Fprintf(&b, "enum %s", typ.Name)
+		printBlock(&b, len(typ.EnumValues), func(i int) {
+			value := typ.EnumValues[i]
+
+			printDescription(&b, value.Description, "  ")
+			fmt.Fprintf(&b, "  %s%s", value.Name, printDeprecated(value.IsDeprecated, value.DeprecationReason))
+		})
+	case "INPUT_OBJECT":
+		fmt.Fprintf(&b, "input %s", typ.Name)
+		printBlock(&b, len(typ.InputFields), func(i int) {
+			printDescription(&b, typ.InputFields[i].Description, "  ")
+			fmt.Fprintf(&b, "  %s", printInputValue(typ.InputFields[i]))
+		})
+	default:
+		fmt.Fprintf(&b, "# %s %s", typ.Kind, typ.Name)
+	}
+
+	return b.String()
+}
+
+// printBlock writes a braced block with a line for each of n items
+func printBlock(b *strings.Builder, n int, printLine func(i int)) {
+	if n == 0 {
+		return
+	}
+
+	b.WriteString(" {\n")
+
+	for i := 0; i < n; i++ {
+		printLine(i)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("}")
+}
+
+func printImplements(interfaces []TypeRef) string {
+	if len(interfaces) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(interfaces))
+	for _, ref := range interfaces {
+		names = append(names, ref.Name)
+	}
+
+	return " implements " + strings.Join(names, " & ")
+}
+
+func printField(b *strings.Builder, field Field) {
+	printDescription(b, field.Description, "  ")
+	fmt.Fprintf(b, "  %s%s: %s%s", field.Name, printArgs(field.Args, "  "), field.Type, printDeprecated(field.IsDeprecated, field.DeprecationReason))
+}
+
+// printArgs renders an argument list, on separate lines if any argument has
+// a description
+func printArgs(args []InputValue, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	hasDescriptions := false
+	for _, arg := range args {
+		if arg.Description != "" {
+			hasDescriptions = true
+		}
+	}
+
+	if !hasDescriptions {
+		printed := make([]string, 0, len(args))
+		for _, arg := range args {
+			printed = append(printed, printInputValue(arg))
+		}
+
+		return "(" + strings.Join(printed, ", ") + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString("(\n")
+
+	for _, arg := range args {
+		printDescription(&b, arg.Description, indent+"  ")
+		fmt.Fprintf(&b, "%s  %s\n", indent, printInputValue(arg))
+	}
+
+	b.WriteString(indent + ")")
+	return b.String()
+}
+
+func printInputValue(value InputValue) string {
+	printed := fmt.Sprintf("%s: %s", value.Name, value.Type)
+	if value.DefaultValue != "" {
+		printed += " = " + value.DefaultValue
+	}
+
+	return printed + printDeprecated(value.IsDeprecated, value.DeprecationReason)
+}
+
+func printDeprecated(isDeprecated bool, reason string) string {
+	if !isDeprecated {
+		return ""
+	}
+
+	if reason == "" || reason == defaultDeprecationReason {
+		return " @deprecated"
+	}
+
+	return fmt.Sprintf(" @deprecated(reason: %s)", querybuilder.StringValue(reason))
+}
+
+// printDescription writes a description as a block string, on a single line
+// if it is short enough
+func printDescription(b *strings.Builder, description string, indent string) {
+	if description == "" {
+		return
+	}
+
+	description = strings.Replace(description, `"""`, `\"""`, -1)
+
+	if !strings.Contains(description, "\n") && !strings.HasSuffix(description, `"`) && len(description) <= 70 {
+		fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", indent, description)
+		return
+	}
+
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(description, "\n") {
+		if line == "" {
+			b.WriteString("\n")
+		} else {
+			fmt.Fprintf(b, "%s%s\n", indent, line)
+		}
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
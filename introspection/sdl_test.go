@@ -0,0 +1,129 @@
+package introspection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintType(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	tests := map[string]string{
+		"GitObjectID": "\"\"\"A Git object ID.\"\"\"\nscalar GitObjectID",
+		"GitObject": `"""Represents a Git object."""
+interface GitObject {
+  oid: GitObjectID!
+}`,
+		"Query": `"""The query root."""
+type Query {
+  """Lookup a given repository by the owner and repository name."""
+  repository(owner: String!, name: String!): Repository
+  """Lookup a repository owner by login."""
+  repositoryOwner(login: String!): RepositoryOwner
+  """Fetches an object given its ID."""
+  node(id: ID!): Node
+  """The currently authenticated user."""
+  viewer: User!
+  """Perform a search."""
+  search(query: String!, type: SearchType!, first: Int = 10): SearchResultItemConnection!
+}`,
+		"SearchResultItem": "\"\"\"The results of a search.\"\"\"\nunion SearchResultItem = Issue | Repository",
+		"IssueState": `"""The possible states of an issue."""
+enum IssueState {
+  """An issue that is still open"""
+  OPEN
+  """An issue that has been closed"""
+  CLOSED
+  LOCKED @deprecated(reason: "Use ` + "`CLOSED`" + ` instead.")
+}`,
+		"IssueOrder": `"""Ways in which lists of issues can be ordered."""
+input IssueOrder {
+  field: IssueOrderField!
+  direction: OrderDirection! = DESC
+}`,
+	}
+
+	for name, expected := range tests {
+		typ, ok := schema.LookupType(name)
+		require.True(t, ok, name)
+		assert.Equal(t, expected, PrintType(typ), name)
+	}
+}
+
+func TestPrintDescription(t *testing.T) {
+	typ := &FullType{
+		Kind:        "OBJECT",
+		Name:        "Thing",
+		Description: "A thing.\n\nWith \"\"\"quotes\"\"\".",
+		Fields: []Field{{
+			Name: "count",
+			Args: []InputValue{
+				{Name: "first", Description: "How many", Type: TypeRef{Kind: "SCALAR", Name: "Int"}},
+			},
+			Type:         TypeRef{Kind: "SCALAR", Name: "Int"},
+			IsDeprecated: true,
+		}},
+	}
+
+	assert.Equal(t, `"""
+A thing.
+
+With \"""quotes\""".
+"""
+type Thing {
+  count(
+    """How many"""
+    first: Int
+  ): Int @deprecated
+}`, PrintType(typ))
+}
+
+func TestPrintSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	sdl := PrintSchema(schema)
+	assert.True(t, strings.HasPrefix(sdl, "\"\"\"A Git object ID.\"\"\"\nscalar GitObjectID\n\n\"\"\"The query root.\"\"\"\ntype Query {\n"))
+	assert.True(t, strings.HasSuffix(sdl, "type AddStarPayload {\n  clientMutationId: String\n  starrable: Repository\n}\n"))
+	assert.NotContains(t, sdl, "scalar String")
+	assert.NotContains(t, sdl, "@include")
+	assert.NotContains(t, sdl, "schema {")
+}
+
+func TestPrintSchemaRoots(t *testing.T) {
+	s := &Schema{
+		Types: []FullType{
+			{Kind: "SCALAR", Name: "String"},
+			{Kind: "OBJECT", Name: "QueryRoot", Fields: []Field{{Name: "hello", Type: TypeRef{Kind: "SCALAR", Name: "String"}}}},
+			{Kind: "OBJECT", Name: "__Schema"},
+		},
+		Directives: []Directive{
+			{Name: "skip", Locations: []string{"FIELD"}},
+			{
+				Name:        "cached",
+				Description: "Caches a field.",
+				Locations:   []string{"FIELD", "FRAGMENT_SPREAD"},
+				Args:        []InputValue{{Name: "ttl", Type: TypeRef{Kind: "SCALAR", Name: "Int"}, DefaultValue: "60"}},
+			},
+		},
+	}
+	s.QueryType.Name = "QueryRoot"
+
+	assert.Equal(t, `schema {
+  query: QueryRoot
+}
+
+"""Caches a field."""
+directive @cached(ttl: Int = 60) on FIELD | FRAGMENT_SPREAD
+
+type QueryRoot {
+  hello: String
+}
+`, PrintSchema(s))
+}
@@ -0,0 +1,833 @@
+package introspection
+
+import (
+	"fmt"
+
+	"github.com/jclem/graphsh/lexer"
+	"github.com/jclem/graphsh/querybuilder"
+)
+
+// ParseSDL parses a GraphQL schema definition language document into a
+// schema, as if it had been introspected
+func ParseSDL(input string) (*Schema, error) {
+	p := &sdlParser{
+		lexer: lexer.New(input),
+		types: map[string]*FullType{},
+		roots: map[string]string{},
+	}
+
+	if err := p.parseDocument(); err != nil {
+		if lexErr, ok := err.(*lexer.Error); ok {
+			line, column := position([]rune(input), lexErr.Column)
+			return nil, fmt.Errorf("Syntax error at line %d, column %d: %s", line, column, lexErr.Message)
+		}
+
+		return nil, err
+	}
+
+	return p.schema()
+}
+
+// position converts a 1-based column of the whole input into a line and a
+// column of that line
+func position(input []rune, offset int) (int, int) {
+	line, column := 1, 1
+
+	for i := 0; i < offset-1 && i < len(input); i++ {
+		if input[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return line, column
+}
+
+var nonNullBoolean = TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "SCALAR", Name: "Boolean"}}
+
+// specifiedDirectives are the directives defined by the GraphQL
+// specification, which a schema supports without defining them
+var specifiedDirectives = []Directive{
+	{
+		Name:      "include",
+		Locations: []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:      []InputValue{{Name: "if", Type: nonNullBoolean}},
+	},
+	{
+		Name:      "skip",
+		Locations: []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:      []InputValue{{Name: "if", Type: nonNullBoolean}},
+	},
+	{
+		Name:      "deprecated",
+		Locations: []string{"FIELD_DEFINITION", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION", "ENUM_VALUE"},
+		Args:      []InputValue{{Name: "reason", Type: TypeRef{Kind: "SCALAR", Name: "String"}, DefaultValue: `"No longer supported"`}},
+	},
+	{
+		Name:      "specifiedBy",
+		Locations: []string{"SCALAR"},
+		Args:      []InputValue{{Name: "url", Type: TypeRef{Kind: "NON_NULL", OfType: &TypeRef{Kind: "SCALAR", Name: "String"}}}},
+	},
+}
+
+type sdlParser struct {
+	lexer *lexer.Lexer
+
+	// types are the defined types, in the order of typeNames
+	types     map[string]*FullType
+	typeNames []string
+
+	directives []Directive
+
+	// extensions are the type extensions, which are applied once every type
+	// is defined, since definitions may come in any order
+	extensions []sdlExtension
+
+	// roots are the root type names given by a schema definition
+	roots map[string]string
+
+	description string
+}
+
+// sdlExtension is a type extension, parsed as a type of its own, along with
+// the token naming the type it extends
+type sdlExtension struct {
+	name lexer.Token
+	typ  *FullType
+}
+
+// sdlDirective is a directive applied to a definition
+type sdlDirective struct {
+	name string
+	args map[string]interface{}
+}
+
+func (p *sdlParser) parseDocument() error {
+	for {
+		tok, err := p.lexer.Peek()
+		if err != nil {
+			return err
+		}
+
+		if tok.Kind == lexer.EOF {
+			return p.applyExtensions()
+		}
+
+		if err := p.parseDefinition(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *sdlParser) parseDefinition() error {
+	description, err := p.parseDescription()
+	if err != nil {
+		return err
+	}
+
+	keyword, err := p.expect(lexer.Name)
+	if err != nil {
+		return err
+	}
+
+	extend := keyword.Value == "extend"
+	if extend {
+		if keyword, err = p.expect(lexer.Name); err != nil {
+			return err
+		}
+	}
+
+	switch keyword.Value {
+	case "schema":
+		return p.parseSchemaDefinition(description)
+	case "directive":
+		return p.parseDirectiveDefinition(description)
+	case "scalar":
+		_, err := p.parseTypeDefinition("SCALAR", description, extend)
+		return err
+	case "type", "interface":
+		kind := "OBJECT"
+		if keyword.Value == "interface" {
+			kind = "INTERFACE"
+		}
+
+		typ, err := p.parseTypeDefinition(kind, description, extend)
+		if err != nil {
+			return err
+		}
+
+		return p.parseFields(typ)
+	case "union":
+		typ, err := p.parseTypeDefinition("UNION", description, extend)
+		if err != nil {
+			return err
+		}
+
+		return p.parseUnionMembers(typ)
+	case "enum":
+		typ, err := p.parseTypeDefinition("ENUM", description, extend)
+		if err != nil {
+			return err
+		}
+
+		return p.parseEnumValues(typ)
+	case "input":
+		typ, err := p.parseTypeDefinition("INPUT_OBJECT", description, extend)
+		if err != nil {
+			return err
+		}
+
+		return p.parseBlock(func() error {
+			value, err := p.parseInputValue()
+			if err != nil {
+				return err
+			}
+
+			typ.InputFields = append(typ.InputFields, *value)
+			return nil
+		})
+	}
+
+	return lexer.Errorf(keyword.Start, "Unexpected %s", keyword)
+}
+
+// parseTypeDefinition parses the name, interfaces, and directives of a type,
+// and returns the type being defined or extended
+func (p *sdlParser) parseTypeDefinition(kind string, description string, extend bool) (*FullType, error) {
+	name, err := p.expect(lexer.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := &FullType{Kind: kind, Name: name.Value, Description: description}
+
+	switch _, exists := p.types[name.Value]; {
+	case extend:
+		typ.Description = ""
+		p.extensions = append(p.extensions, sdlExtension{name: name, typ: typ})
+	case exists:
+		return nil, lexer.Errorf(name.Start, "Duplicate type %q", name.Value)
+	default:
+		p.types[name.Value] = typ
+		p.typeNames = append(p.typeNames, name.Value)
+	}
+
+	if kind == "OBJECT" || kind == "INTERFACE" {
+		interfaces, err := p.parseImplements()
+		if err != nil {
+			return nil, err
+		}
+
+		typ.Interfaces = append(typ.Interfaces, interfaces...)
+	}
+
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	if url, ok := specifiedBy(directives); ok && kind == "SCALAR" {
+		typ.SpecifiedByURL = url
+	}
+
+	return typ, nil
+}
+
+// applyExtensions adds the interfaces, fields, members, and values of each
+// type extension to the type it extends
+func (p *sdlParser) applyExtensions() error {
+	for _, extension := range p.extensions {
+		typ, exists := p.types[extension.name.Value]
+
+		switch {
+		case !exists:
+			return lexer.Errorf(extension.name.Start, "Can not extend undefined type %q", extension.name.Value)
+		case typ.Kind != extension.typ.Kind:
+			return lexer.Errorf(extension.name.Start, "Can not extend %s type %q as %s", typ.Kind, extension.name.Value, extension.typ.Kind)
+		}
+
+		typ.Interfaces = append(typ.Interfaces, extension.typ.Interfaces...)
+		typ.Fields = append(typ.Fields, extension.typ.Fields...)
+		typ.PossibleTypes = append(typ.PossibleTypes, extension.typ.PossibleTypes...)
+		typ.EnumValues = append(typ.EnumValues, extension.typ.EnumValues...)
+		typ.InputFields = append(typ.InputFields, extension.typ.InputFields...)
+
+		if extension.typ.SpecifiedByURL != "" {
+			typ.SpecifiedByURL = extension.typ.SpecifiedByURL
+		}
+	}
+
+	return nil
+}
+
+func (p *sdlParser) parseImplements() ([]TypeRef, error) {
+	if ok, err := p.skipKeyword("implements"); err != nil || !ok {
+		return nil, err
+	}
+
+	if _, err := p.skip(lexer.Amp); err != nil {
+		return nil, err
+	}
+
+	var interfaces []TypeRef
+
+	for {
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		interfaces = append(interfaces, TypeRef{Name: name.Value})
+
+		if ok, err := p.skip(lexer.Amp); err != nil || !ok {
+			return interfaces, err
+		}
+	}
+}
+
+func (p *sdlParser) parseFields(typ *FullType) error {
+	return p.parseBlock(func() error {
+		description, err := p.parseDescription()
+		if err != nil {
+			return err
+		}
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return err
+		}
+
+		field := Field{Name: name.Value, Description: description}
+
+		if field.Args, err = p.parseArgumentDefinitions(); err != nil {
+			return err
+		}
+
+		if _, err := p.expect(lexer.Colon); err != nil {
+			return err
+		}
+
+		if field.Type, err = p.parseTypeRef(); err != nil {
+			return err
+		}
+
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return err
+		}
+
+		field.IsDeprecated, field.DeprecationReason = deprecation(directives)
+		typ.Fields = append(typ.Fields, field)
+
+		return nil
+	})
+}
+
+// parseArgumentDefinitions parses an optional parenthesized list of argument
+// definitions
+func (p *sdlParser) parseArgumentDefinitions() ([]InputValue, error) {
+	var args []InputValue
+
+	if ok, err := p.skip(lexer.ParenL); err != nil || !ok {
+		return nil, err
+	}
+
+	for {
+		if ok, err := p.skip(lexer.ParenR); err != nil || ok {
+			return args, err
+		}
+
+		arg, err := p.parseInputValue()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, *arg)
+	}
+}
+
+func (p *sdlParser) parseInputValue() (*InputValue, error) {
+	description, err := p.parseDescription()
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := p.expect(lexer.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	value := &InputValue{Name: name.Value, Description: description}
+
+	if _, err := p.expect(lexer.Colon); err != nil {
+		return nil, err
+	}
+
+	if value.Type, err = p.parseTypeRef(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skip(lexer.Equals); err != nil {
+		return nil, err
+	} else if ok {
+		defaultValue, err := querybuilder.ReadValue(p.lexer)
+		if err != nil {
+			return nil, err
+		}
+
+		value.DefaultValue = defaultValue.String()
+	}
+
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+
+	value.IsDeprecated, value.DeprecationReason = deprecation(directives)
+
+	return value, nil
+}
+
+// parseTypeRef parses a type reference, whose named type's kind is resolved
+// once every type is defined
+func (p *sdlParser) parseTypeRef() (TypeRef, error) {
+	var ref TypeRef
+
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return ref, err
+	}
+
+	switch tok.Kind {
+	case lexer.BracketL:
+		ofType, err := p.parseTypeRef()
+		if err != nil {
+			return ref, err
+		}
+
+		if _, err := p.expect(lexer.BracketR); err != nil {
+			return ref, err
+		}
+
+		ref = TypeRef{Kind: "LIST", OfType: &ofType}
+	case lexer.Name:
+		ref = TypeRef{Name: tok.Value}
+	default:
+		return ref, lexer.Errorf(tok.Start, "Expected a type, found %s", tok)
+	}
+
+	if ok, err := p.skip(lexer.Bang); err != nil {
+		return ref, err
+	} else if ok {
+		ofType := ref
+		ref = TypeRef{Kind: "NON_NULL", OfType: &ofType}
+	}
+
+	return ref, nil
+}
+
+func (p *sdlParser) parseUnionMembers(typ *FullType) error {
+	if ok, err := p.skip(lexer.Equals); err != nil || !ok {
+		return err
+	}
+
+	if _, err := p.skip(lexer.Pipe); err != nil {
+		return err
+	}
+
+	for {
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return err
+		}
+
+		typ.PossibleTypes = append(typ.PossibleTypes, TypeRef{Name: name.Value})
+
+		if ok, err := p.skip(lexer.Pipe); err != nil || !ok {
+			return err
+		}
+	}
+}
+
+func (p *sdlParser) parseEnumValues(typ *FullType) error {
+	return p.parseBlock(func() error {
+		description, err := p.parseDescription()
+		if err != nil {
+			return err
+		}
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return err
+		}
+
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return err
+		}
+
+		isDeprecated, reason := deprecation(directives)
+
+		typ.EnumValues = append(typ.EnumValues, EnumValue{
+			Name:              name.Value,
+			Description:       description,
+			IsDeprecated:      isDeprecated,
+			DeprecationReason: reason,
+		})
+
+		return nil
+	})
+}
+
+func (p *sdlParser) parseSchemaDefinition(description string) error {
+	if description != "" {
+		p.description = description
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return err
+	}
+
+	return p.parseBlock(func() error {
+		operation, err := p.expect(lexer.Name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := p.expect(lexer.Colon); err != nil {
+			return err
+		}
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return err
+		}
+
+		switch operation.Value {
+		case "query", "mutation", "subscription":
+			p.roots[operation.Value] = name.Value
+		default:
+			return lexer.Errorf(operation.Start, "Unknown operation type %q", operation.Value)
+		}
+
+		return nil
+	})
+}
+
+func (p *sdlParser) parseDirectiveDefinition(description string) error {
+	if _, err := p.expect(lexer.At); err != nil {
+		return err
+	}
+
+	name, err := p.expect(lexer.Name)
+	if err != nil {
+		return err
+	}
+
+	directive := Directive{Name: name.Value, Description: description}
+
+	if directive.Args, err = p.parseArgumentDefinitions(); err != nil {
+		return err
+	}
+
+	if directive.IsRepeatable, err = p.skipKeyword("repeatable"); err != nil {
+		return err
+	}
+
+	if ok, err := p.skipKeyword("on"); err != nil {
+		return err
+	} else if !ok {
+		tok, _ := p.lexer.Peek()
+		return lexer.Errorf(tok.Start, "Expected \"on\", found %s", tok)
+	}
+
+	if _, err := p.skip(lexer.Pipe); err != nil {
+		return err
+	}
+
+	for {
+		location, err := p.expect(lexer.Name)
+		if err != nil {
+			return err
+		}
+
+		directive.Locations = append(directive.Locations, location.Value)
+
+		if ok, err := p.skip(lexer.Pipe); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+	}
+
+	p.directives = append(p.directives, directive)
+
+	return nil
+}
+
+func (p *sdlParser) parseDirectives() ([]sdlDirective, error) {
+	var directives []sdlDirective
+
+	for {
+		if ok, err := p.skip(lexer.At); err != nil || !ok {
+			return directives, err
+		}
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := querybuilder.ReadArguments(p.lexer)
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, sdlDirective{name: name.Value, args: args})
+	}
+}
+
+// deprecation finds whether a definition is deprecated, and why
+func deprecation(directives []sdlDirective) (bool, string) {
+	for _, directive := range directives {
+		if directive.name != "deprecated" {
+			continue
+		}
+
+		if reason, ok := directive.args["reason"].(querybuilder.StringValue); ok {
+			return true, string(reason)
+		}
+
+		return true, defaultDeprecationReason
+	}
+
+	return false, ""
+}
+
+// specifiedBy finds the URL of a scalar's specification
+func specifiedBy(directives []sdlDirective) (string, bool) {
+	for _, directive := range directives {
+		if url, ok := directive.args["url"].(querybuilder.StringValue); ok && directive.name == "specifiedBy" {
+			return string(url), true
+		}
+	}
+
+	return "", false
+}
+
+func (p *sdlParser) parseDescription() (string, error) {
+	tok, err := p.lexer.Peek()
+	if err != nil {
+		return "", err
+	}
+
+	if tok.Kind != lexer.String && tok.Kind != lexer.BlockString {
+		return "", nil
+	}
+
+	p.lexer.Next()
+
+	return tok.Value, nil
+}
+
+// parseBlock parses an optional braced block, calling parseItem for each item
+func (p *sdlParser) parseBlock(parseItem func() error) error {
+	if ok, err := p.skip(lexer.BraceL); err != nil || !ok {
+		return err
+	}
+
+	for {
+		if ok, err := p.skip(lexer.BraceR); err != nil || ok {
+			return err
+		}
+
+		if err := parseItem(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *sdlParser) expect(kind lexer.Kind) (lexer.Token, error) {
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return tok, err
+	}
+
+	if tok.Kind != kind {
+		return tok, lexer.Errorf(tok.Start, "Expected %s, found %s", kind, tok)
+	}
+
+	return tok, nil
+}
+
+func (p *sdlParser) skip(kind lexer.Kind) (bool, error) {
+	tok, err := p.lexer.Peek()
+	if err != nil || tok.Kind != kind {
+		return false, err
+	}
+
+	_, err = p.lexer.Next()
+	return true, err
+}
+
+func (p *sdlParser) skipKeyword(keyword string) (bool, error) {
+	tok, err := p.lexer.Peek()
+	if err != nil || tok.Kind != lexer.Name || tok.Value != keyword {
+		return false, err
+	}
+
+	_, err = p.lexer.Next()
+	return true, err
+}
+
+// schema builds the parsed schema, adding the built-in scalars and
+// directives and resolving type references
+func (p *sdlParser) schema() (*Schema, error) {
+	for _, name := range builtInScalars {
+		if _, ok := p.types[name]; !ok {
+			p.types[name] = &FullType{Kind: "SCALAR", Name: name}
+			p.typeNames = append(p.typeNames, name)
+		}
+	}
+
+	// Possible types of interfaces are the objects that implement them
+	for _, name := range p.typeNames {
+		typ := p.types[name]
+		if typ.Kind != "OBJECT" {
+			continue
+		}
+
+		for _, ref := range typ.Interfaces {
+			if iface, ok := p.types[ref.Name]; ok && iface.Kind == "INTERFACE" {
+				iface.PossibleTypes = append(iface.PossibleTypes, TypeRef{Name: typ.Name})
+			}
+		}
+	}
+
+	s := &Schema{Description: p.description, Directives: p.directives}
+
+	for _, name := range p.typeNames {
+		s.Types = append(s.Types, *p.types[name])
+	}
+
+	for _, directive := range specifiedDirectives {
+		if _, ok := s.getDirective(directive.Name); !ok {
+			s.Directives = append(s.Directives, directive)
+		}
+	}
+
+	for i := range s.Types {
+		if err := p.resolveType(&s.Types[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range s.Directives {
+		for j := range s.Directives[i].Args {
+			if err := p.resolveTypeRef(&s.Directives[i].Args[j].Type); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := p.resolveRoots(s); err != nil {
+		return nil, err
+	}
+
+	s.index()
+
+	return s, nil
+}
+
+func (p *sdlParser) resolveRoots(s *Schema) error {
+	roots := map[string]string{}
+
+	for _, operation := range []string{"query", "mutation", "subscription"} {
+		name, ok := p.roots[operation]
+		if !ok {
+			// Without a schema definition, the root types have conventional names
+			if len(p.roots) > 0 {
+				continue
+			}
+
+			name = map[string]string{"query": "Query", "mutation": "Mutation", "subscription": "Subscription"}[operation]
+			if _, exists := p.types[name]; !exists {
+				continue
+			}
+		}
+
+		if typ, exists := p.types[name]; !exists || typ.Kind != "OBJECT" {
+			return fmt.Errorf("The %s root type %q is not a defined object type", operation, name)
+		}
+
+		roots[operation] = name
+	}
+
+	if roots["query"] == "" {
+		return fmt.Errorf("Schema has no query root type")
+	}
+
+	s.QueryType.Name = roots["query"]
+
+	if name, ok := roots["mutation"]; ok {
+		s.MutationType = &struct{ Name string }{name}
+	}
+
+	if name, ok := roots["subscription"]; ok {
+		s.SubscriptionType = &struct{ Name string }{name}
+	}
+
+	return nil
+}
+
+func (p *sdlParser) resolveType(typ *FullType) error {
+	for i := range typ.Fields {
+		if err := p.resolveTypeRef(&typ.Fields[i].Type); err != nil {
+			return err
+		}
+
+		for j := range typ.Fields[i].Args {
+			if err := p.resolveTypeRef(&typ.Fields[i].Args[j].Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range typ.InputFields {
+		if err := p.resolveTypeRef(&typ.InputFields[i].Type); err != nil {
+			return err
+		}
+	}
+
+	for i := range typ.Interfaces {
+		if err := p.resolveTypeRef(&typ.Interfaces[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range typ.PossibleTypes {
+		if err := p.resolveTypeRef(&typ.PossibleTypes[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveTypeRef sets the kind of the named type of a type reference
+func (p *sdlParser) resolveTypeRef(ref *TypeRef) error {
+	for ref.OfType != nil {
+		ref = ref.OfType
+	}
+
+	typ, ok := p.types[ref.Name]
+	if !ok {
+		return fmt.Errorf("Unknown type %q", ref.Name)
+	}
+
+	ref.Kind = typ.Kind
+
+	return nil
+}
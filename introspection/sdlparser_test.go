@@ -0,0 +1,177 @@
+package introspection
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSDLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	sdl := PrintSchema(schema)
+
+	parsed, err := ParseSDL(sdl)
+	require.NoError(t, err)
+	assert.Equal(t, sdl, PrintSchema(parsed))
+
+	owner, ok := parsed.LookupType("RepositoryOwner")
+	require.True(t, ok)
+	assert.Equal(t, []TypeRef{{Kind: "OBJECT", Name: "User"}, {Kind: "OBJECT", Name: "Organization"}}, owner.PossibleTypes)
+
+	_, ok = parsed.getDirective("include")
+	assert.True(t, ok, "Specified directives are always supported")
+}
+
+func TestParseSDL(t *testing.T) {
+	parsed, err := ParseSDL(`
+schema { query: Root }
+
+"""
+A custom directive
+"""
+directive @cached(ttl: Int = 60) repeatable on FIELD | QUERY
+
+# Comments are ignored
+type Root {
+  "The thing"
+  thing(id: ID!, kinds: [Kind!] = [A]): Thing @deprecated
+  old: String @deprecated(reason: "Use thing")
+}
+
+interface Thing { id: ID! }
+
+type A implements Thing { id: ID! }
+
+enum Kind { A, B @deprecated }
+
+union Any = | A
+
+extend type A { name: String }
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Root", parsed.QueryType.Name)
+	assert.Nil(t, parsed.MutationType)
+
+	root, err := parsed.GetRootType("query")
+	require.NoError(t, err)
+	assert.Equal(t, `type Root {
+  """The thing"""
+  thing(id: ID!, kinds: [Kind!] = [A]): Thing @deprecated
+  old: String @deprecated(reason: "Use thing")
+}`, PrintType(root))
+
+	a, _ := parsed.LookupType("A")
+	assert.Equal(t, "type A implements Thing {\n  id: ID!\n  name: String\n}", PrintType(a))
+
+	thing, _ := parsed.LookupType("Thing")
+	assert.Equal(t, []TypeRef{{Kind: "OBJECT", Name: "A"}}, thing.PossibleTypes)
+
+	cached, ok := parsed.getDirective("cached")
+	require.True(t, ok)
+	assert.Equal(t, `"""A custom directive"""`+"\ndirective @cached(ttl: Int = 60) repeatable on FIELD | QUERY", PrintDirective(*cached))
+
+	_, ok = parsed.LookupType("Boolean")
+	assert.True(t, ok, "Built-in scalars are always defined")
+}
+
+func TestParseSDLExtensionsFirst(t *testing.T) {
+	// Definition order is not significant, so types can be extended before
+	// they are defined
+	parsed, err := ParseSDL(`
+extend type Query { b: Int }
+extend enum Kind { C }
+extend union Any = B
+extend type A implements Node
+
+type Query { a: A }
+type A { id: ID! }
+type B { id: ID! }
+interface Node { id: ID! }
+enum Kind { A, B }
+union Any = A
+`)
+	require.NoError(t, err)
+
+	query, _ := parsed.GetQueryType()
+	assert.Equal(t, "type Query {\n  a: A\n  b: Int\n}", PrintType(query))
+
+	a, _ := parsed.LookupType("A")
+	assert.Equal(t, "type A implements Node {\n  id: ID!\n}", PrintType(a))
+
+	kind, _ := parsed.LookupType("Kind")
+	assert.Equal(t, "enum Kind {\n  A\n  B\n  C\n}", PrintType(kind))
+
+	union, _ := parsed.LookupType("Any")
+	assert.Equal(t, "union Any = A | B", PrintType(union))
+}
+
+func TestParseSDLIntrospectionFields(t *testing.T) {
+	sdl := `"""The API"""
+schema {
+  query: Query
+}
+
+directive @cached repeatable on FIELD
+
+type Query {
+  search(text: String, query: String @deprecated(reason: "Use text")): [URL]
+}
+
+scalar URL @specifiedBy(url: "https://url.spec.whatwg.org")
+
+input Filter {
+  limit: Int @deprecated
+}
+`
+
+	parsed, err := ParseSDL(sdl)
+	require.NoError(t, err)
+	assert.Equal(t, "The API", parsed.Description)
+	assert.Equal(t, sdl, PrintSchema(parsed))
+
+	filter, _ := parsed.LookupType("Filter")
+	assert.True(t, filter.InputFields[0].IsDeprecated)
+}
+
+func TestParseSDLErrors(t *testing.T) {
+	tests := map[string]string{
+		"type Query {\n  a: String\n  b String\n}":       `Syntax error at line 3, column 5: Expected ":", found name "String"`,
+		"type Query { a: Nope }":                         `Unknown type "Nope"`,
+		"type Foo { a: String }":                         "Schema has no query root type",
+		"type Query { a: Int }\ntype Query { b: Int }":   `Syntax error at line 2, column 6: Duplicate type "Query"`,
+		"extend type Query { a: Int }":                   `Syntax error at line 1, column 13: Can not extend undefined type "Query"`,
+		"extend enum Query { A }\ntype Query { a: Int }": `Syntax error at line 1, column 13: Can not extend OBJECT type "Query" as ENUM`,
+		"schema { query: Root } type Query { a: Int }":   `The query root type "Root" is not a defined object type`,
+	}
+
+	for input, expected := range tests {
+		_, err := ParseSDL(input)
+		assert.EqualError(t, err, expected, input)
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/schema.json")
+	require.NoError(t, err)
+
+	parsed, err := ParseSchema(data)
+	require.NoError(t, err)
+	assert.Equal(t, "Query", parsed.QueryType.Name)
+
+	parsed, err = ParseSchema([]byte(`{"__schema": {"queryType": {"name": "Root"}, "types": []}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Root", parsed.QueryType.Name)
+
+	parsed, err = ParseSchema([]byte("  type Query { a: Int }"))
+	require.NoError(t, err)
+	assert.Equal(t, "Query", parsed.QueryType.Name)
+
+	_, err = ParseSchema([]byte(`{"data": {}}`))
+	assert.EqualError(t, err, `Expected an introspection result with a "__schema" field`)
+}
@@ -0,0 +1,194 @@
+package introspection
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SearchResult is a type, field, argument, input field, or enum value whose
+// name or description matches a search
+type SearchResult struct {
+	// Path is the schema coordinate of the result, such as `Repository`,
+	// `Repository.issues`, or `Repository.issues(states:)`
+	Path        string
+	Type        TypeRef
+	Description string
+
+	// score ranks the result, with better matches lower
+	score int
+}
+
+// Match scores, from best to worst
+const (
+	exactMatch = iota
+	prefixMatch
+	substringMatch
+	fuzzyMatch
+	descriptionMatch
+)
+
+// matcher scores how well a name and description match a search
+type matcher func(name string, description string) (int, bool)
+
+// Search finds the types, fields, arguments, input fields, and enum values
+// whose names or descriptions match a pattern, best matches first
+//
+// A pattern surrounded by slashes is a regular expression. Any other pattern
+// matches names containing its characters in order and descriptions
+// containing it, ignoring case. Only the named types are searched, or every
+// type if names is nil.
+func (s *Schema) Search(pattern string, names []string) ([]SearchResult, error) {
+	match, err := newMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var within map[string]bool
+	if names != nil {
+		within = make(map[string]bool, len(names))
+		for _, name := range names {
+			within[name] = true
+		}
+	}
+
+	var results []SearchResult
+
+	add := func(path string, name string, description string, ref TypeRef) {
+		if score, ok := match(name, description); ok {
+			results = append(results, SearchResult{Path: path, Type: ref, Description: description, score: score})
+		}
+	}
+
+	for i := range s.Types {
+		typ := &s.Types[i]
+
+		if strings.HasPrefix(typ.Name, "__") || (within != nil && !within[typ.Name]) {
+			continue
+		}
+
+		add(typ.Name, typ.Name, typ.Description, TypeRef{Kind: typ.Kind, Name: typ.Name})
+
+		for _, field := range typ.Fields {
+			path := typ.Name + "." + field.Name
+			add(path, field.Name, field.Description, field.Type)
+
+			for _, arg := range field.Args {
+				add(fmt.Sprintf("%s(%s:)", path, arg.Name), arg.Name, arg.Description, arg.Type)
+			}
+		}
+
+		for _, field := range typ.InputFields {
+			add(typ.Name+"."+field.Name, field.Name, field.Description, field.Type)
+		}
+
+		for _, value := range typ.EnumValues {
+			add(typ.Name+"."+value.Name, value.Name, value.Description, TypeRef{Kind: typ.Kind, Name: typ.Name})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score < results[j].score
+	})
+
+	return results, nil
+}
+
+func newMatcher(pattern string) (matcher, error) {
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid regular expression %s: %s", pattern, err)
+		}
+
+		return func(name string, description string) (int, bool) {
+			switch {
+			case re.MatchString(name):
+				return exactMatch, true
+			case re.MatchString(description):
+				return descriptionMatch, true
+			}
+
+			return 0, false
+		}, nil
+	}
+
+	pattern = strings.ToLower(pattern)
+
+	return func(name string, description string) (int, bool) {
+		name = strings.ToLower(name)
+
+		switch {
+		case name == pattern:
+			return exactMatch, true
+		case strings.HasPrefix(name, pattern):
+			return prefixMatch, true
+		case strings.Contains(name, pattern):
+			return substringMatch, true
+		case isSubsequence(pattern, name):
+			return fuzzyMatch, true
+		case strings.Contains(strings.ToLower(description), pattern):
+			return descriptionMatch, true
+		}
+
+		return 0, false
+	}, nil
+}
+
+// isSubsequence is whether s contains the characters of sub in order
+func isSubsequence(sub string, s string) bool {
+	runes := []rune(sub)
+	if len(runes) == 0 {
+		return true
+	}
+
+	for _, r := range s {
+		if r == runes[0] {
+			runes = runes[1:]
+			if len(runes) == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReachableTypes gets the names of the types that can be reached from a type
+// through fields, possible types, and the types of arguments and input
+// fields, including the type itself
+func (s *Schema) ReachableTypes(name string) []string {
+	seen := map[string]bool{name: true}
+	names := []string{name}
+
+	for i := 0; i < len(names); i++ {
+		typ, ok := s.LookupType(names[i])
+		if !ok {
+			continue
+		}
+
+		var refs []TypeRef
+		refs = append(refs, typ.PossibleTypes...)
+
+		for _, field := range typ.Fields {
+			refs = append(refs, field.Type)
+			for _, arg := range field.Args {
+				refs = append(refs, arg.Type)
+			}
+		}
+
+		for _, field := range typ.InputFields {
+			refs = append(refs, field.Type)
+		}
+
+		for _, ref := range refs {
+			if named := ref.NamedType(); !seen[named] {
+				seen[named] = true
+				names = append(names, named)
+			}
+		}
+	}
+
+	return names
+}
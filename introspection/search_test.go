@@ -0,0 +1,61 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchPaths(t *testing.T, schema *Schema, pattern string, names []string) []string {
+	results, err := schema.Search(pattern, names)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, result := range results {
+		paths = append(paths, result.Path)
+	}
+
+	return paths
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	// Exact, prefix, substring, and fuzzy name matches come before descriptions
+	paths := searchPaths(t, schema, "issueCount", nil)
+	assert.Equal(t, "SearchResultItemConnection.issueCount", paths[0])
+
+	paths = searchPaths(t, schema, "isscnt", nil)
+	assert.Equal(t, []string{"IssueConnection", "SearchResultItemConnection.issueCount"}, paths)
+
+	paths = searchPaths(t, schema, "discuss ideas", nil)
+	assert.Equal(t, []string{"Issue"}, paths)
+
+	paths = searchPaths(t, schema, "stat", nil)
+	assert.Equal(t, []string{"Repository.issues(states:)", "Issue.state", "IssueState"}, paths[:3])
+
+	// Arguments, input fields, and enum values are searched
+	paths = searchPaths(t, schema, "/^(LOCKED|direction|states)$/", nil)
+	assert.Equal(t, []string{"Repository.issues(states:)", "IssueState.LOCKED", "IssueOrder.direction"}, paths)
+
+	paths = searchPaths(t, schema, "/^login$/", []string{"User"})
+	assert.Equal(t, []string{"User.login"}, paths)
+
+	_, err := schema.Search("/[/", nil)
+	assert.EqualError(t, err, "Invalid regular expression /[/: error parsing regexp: missing closing ]: `[`")
+}
+
+func TestReachableTypes(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	assert.Equal(t, []string{"Blob", "GitObjectID", "String"}, schema.ReachableTypes("Blob"))
+
+	// Possible types, argument types, and input field types are reachable
+	assert.Contains(t, schema.ReachableTypes("RepositoryOwner"), "User")
+	assert.Contains(t, schema.ReachableTypes("Repository"), "IssueOrderField")
+}
@@ -1,130 +1,21 @@
 package introspection
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/iancoleman/strcase"
 )
 
-var schemaQuery = `
-query IntrospectionQuery {
-  __schema {
-    queryType {
-      name
-    }
-    mutationType {
-      name
-    }
-    subscriptionType {
-      name
-    }
-    types {
-      ...FullType
-    }
-    directives {
-      name
-      description
-      locations
-      args {
-        ...InputValue
-      }
-    }
-  }
-}
-
-fragment FullType on __Type {
-  kind
-  name
-  description
-  fields(includeDeprecated: true) {
-    name
-    description
-    args {
-      ...InputValue
-    }
-    type {
-      ...TypeRef
-    }
-    isDeprecated
-    deprecationReason
-  }
-  inputFields {
-    ...InputValue
-  }
-  interfaces {
-    ...TypeRef
-  }
-  enumValues(includeDeprecated: true) {
-    name
-    description
-    isDeprecated
-    deprecationReason
-  }
-  possibleTypes {
-    ...TypeRef
-  }
-}
-
-fragment InputValue on __InputValue {
-  name
-  description
-  type {
-    ...TypeRef
-  }
-  defaultValue
-}
-
-fragment TypeRef on __Type {
-  kind
-  name
-  ofType {
-    kind
-    name
-    ofType {
-      kind
-      name
-      ofType {
-        kind
-        name
-        ofType {
-          kind
-          name
-          ofType {
-            kind
-            name
-            ofType {
-              kind
-              name
-              ofType {
-                kind
-                name
-                ofType {
-                  kind
-                  name
-									ofType {
-										kind
-										name
-									}
-                }
-              }
-            }
-          }
-        }
-      }
-    }
-  }
-}
-`
-
 type introspection struct {
-	Data struct {
-		Schema Schema `json:"__schema"`
-	}
+	Schema Schema `json:"__schema"`
 }
 
 // Schema is a GraphQL schema, the result of an introspection query
 type Schema struct {
+	Description string
+
 	QueryType struct {
 		Name string
 	}
@@ -139,24 +30,111 @@ type Schema struct {
 
 	Types []FullType
 
-	Directives []struct {
-		Name        string
-		Description string
-		Locations   []string
-		Args        []inputValue
+	Directives []Directive
+
+	// typeIndex maps type names to their index in Types
+	typeIndex map[string]int
+}
+
+// Directive is a directive supported by a GraphQL schema
+type Directive struct {
+	Name         string
+	Description  string
+	IsRepeatable bool
+	Locations    []string
+	Args         []InputValue
+}
+
+// isTruncated is whether any of the directive's type references are nested
+// deeper than an introspection query of the given depth could describe
+func (d *Directive) isTruncated(depth int) bool {
+	for i := range d.Args {
+		if d.Args[i].Type.isTruncated(depth) {
+			return true
+		}
 	}
+
+	return false
 }
 
 // GetQueryType returns the full query type
-func (s Schema) GetQueryType() (*FullType, bool) {
-	return s.GetType(s.QueryType.Name)
+func (s *Schema) GetQueryType() (*FullType, bool) {
+	return s.LookupType(s.QueryType.Name)
+}
+
+// GetRootType returns the root type for an operation type, such as "query"
+// or "mutation"
+func (s *Schema) GetRootType(operation string) (*FullType, error) {
+	var name string
+
+	switch operation {
+	case "query":
+		name = s.QueryType.Name
+	case "mutation":
+		if s.MutationType == nil {
+			return nil, errors.New("Schema does not support mutations")
+		}
+		name = s.MutationType.Name
+	case "subscription":
+		if s.SubscriptionType == nil {
+			return nil, errors.New("Schema does not support subscriptions")
+		}
+		name = s.SubscriptionType.Name
+	default:
+		return nil, fmt.Errorf("Unknown operation type %q", operation)
+	}
+
+	typ, ok := s.LookupType(name)
+	if !ok {
+		return nil, fmt.Errorf("Missing %s root type %q", operation, name)
+	}
+
+	return typ, nil
+}
+
+// LookupType returns a type with the given name if one exists
+//
+// Schemas that were loaded or parsed are indexed by type name, and other
+// schemas, such as those built as literals, are searched instead.
+func (s *Schema) LookupType(name string) (*FullType, bool) {
+	if s.typeIndex != nil {
+		i, ok := s.typeIndex[name]
+		if !ok {
+			return nil, false
+		}
+
+		return &s.Types[i], true
+	}
+
+	for i := range s.Types {
+		if s.Types[i].Name == name {
+			return &s.Types[i], true
+		}
+	}
+
+	return nil, false
 }
 
-// GetType returns a type with the given name if one exists
-func (s Schema) GetType(name string) (*FullType, bool) {
-	for _, t := range s.Types {
-		if t.Name == name {
-			return &t, true
+// index builds the maps used to look up types and fields by name, so that
+// lookups need not scan every type and field
+func (s *Schema) index() {
+	s.typeIndex = make(map[string]int, len(s.Types))
+
+	for i := range s.Types {
+		typ := &s.Types[i]
+		s.typeIndex[typ.Name] = i
+
+		typ.fieldIndex = make(map[string]int, len(typ.Fields))
+		for j := range typ.Fields {
+			typ.fieldIndex[typ.Fields[j].Name] = j
+		}
+	}
+}
+
+func (s *Schema) getDirective(name string) (*Directive, bool) {
+	for i := range s.Directives {
+		if s.Directives[i].Name == name {
+			return &s.Directives[i], true
 		}
 	}
 
@@ -165,46 +143,141 @@ func (s Schema) GetType(name string) (*FullType, bool) {
 
 // FullType is a full type description
 type FullType struct {
-	Kind        string
-	Name        string
-	Description string
-	Fields      []Field
-	InputFields []inputValue
-	Interfaces  []typeRef
-	EnumValues  []struct {
-		Name              string
-		Description       string
-		IsDeprecated      bool
-		DeprecationReason string
-	}
-	PossibleTypes []typeRef
+	Kind           string
+	Name           string
+	Description    string
+	SpecifiedByURL string
+	Fields         []Field
+	InputFields    []InputValue
+	Interfaces     []TypeRef
+	EnumValues     []EnumValue
+	PossibleTypes  []TypeRef
+
+	// fieldIndex maps field names to their index in Fields
+	fieldIndex map[string]int
 }
 
-// GetField gets a field with the given name, if it exists
-func (t FullType) GetField(name string) (*Field, bool) {
-	for _, f := range t.Fields {
-		if f.Name == name {
-			return &f, true
+// EnumValue is a value of an enum type
+type EnumValue struct {
+	Name              string
+	Description       string
+	IsDeprecated      bool
+	DeprecationReason string
+}
+
+// GetField gets a field with the given name, if it exists, searching the
+// type's fields if its schema was not indexed
+func (t *FullType) GetField(name string) (*Field, bool) {
+	if t.fieldIndex != nil {
+		i, ok := t.fieldIndex[name]
+		if !ok {
+			return nil, false
+		}
+
+		return &t.Fields[i], true
+	}
+
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i], true
 		}
 	}
 
 	return nil, false
 }
 
+// isTruncated is whether any of the type's type references are nested deeper
+// than an introspection query of the given depth could describe
+func (t *FullType) isTruncated(depth int) bool {
+	for i := range t.Fields {
+		if t.Fields[i].Type.isTruncated(depth) {
+			return true
+		}
+
+		for j := range t.Fields[i].Args {
+			if t.Fields[i].Args[j].Type.isTruncated(depth) {
+				return true
+			}
+		}
+	}
+
+	for i := range t.InputFields {
+		if t.InputFields[i].Type.isTruncated(depth) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Field represents a field of a GraphQL type
 type Field struct {
 	Name              string
 	Description       string
-	Args              []inputValue
-	Type              typeRef
+	Args              []InputValue
+	Type              TypeRef
 	IsDeprecated      bool
 	DeprecationReason string
 }
 
 // GetTypeName gets the name of the field's type
 func (f Field) GetTypeName() string {
-	t := f.Type
+	return f.Type.NamedType()
+}
+
+var emptyKinds = []string{"INTERFACE", "NON_NULL", "OBJECT"}
+
+// GetHumanTypeName is a human-readable type name
+func (f Field) GetHumanTypeName() string {
+	return f.Type.HumanName()
+}
+
+func isEmptyKind(kind string) bool {
+	for _, emptyKind := range emptyKinds {
+		if emptyKind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InputValue is an argument or input field of a GraphQL type
+type InputValue struct {
+	Name              string
+	Description       string
+	Type              TypeRef
+	DefaultValue      string
+	IsDeprecated      bool
+	DeprecationReason string
+}
 
+// GetTypeName gets the name of the input value's type
+func (v InputValue) GetTypeName() string {
+	return v.Type.NamedType()
+}
+
+// GetHumanTypeName is a human-readable type name
+func (v InputValue) GetHumanTypeName() string {
+	return v.Type.HumanName()
+}
+
+// IsRequired is whether the argument or input field must be given, which is
+// when it is non-null and has no default value
+func (v InputValue) IsRequired() bool {
+	return v.Type.Kind == "NON_NULL" && v.DefaultValue == ""
+}
+
+// TypeRef is a reference to a GraphQL type, possibly wrapped in list and
+// non-null types
+type TypeRef struct {
+	Kind   string
+	Name   string
+	OfType *TypeRef
+}
+
+// NamedType gets the name of the type wrapped by any list and non-null types
+func (t TypeRef) NamedType() string {
 	for {
 		if t.OfType == nil {
 			return t.Name
@@ -214,13 +287,28 @@ func (f Field) GetTypeName() string {
 	}
 }
 
-var emptyKinds = []string{"INTERFACE", "NON_NULL", "OBJECT"}
+// String renders the type reference in GraphQL notation, such as `[String!]!`
+func (t TypeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType != nil {
+			return fmt.Sprintf("%s!", t.OfType)
+		}
+	case "LIST":
+		if t.OfType != nil {
+			return fmt.Sprintf("[%s]", t.OfType)
+		}
+	}
 
-// GetHumanTypeName is a human-readable type name
-func (f Field) GetHumanTypeName() string {
-	t := &f.Type
+	return t.Name
+}
+
+// HumanName is a human-readable name for the type reference, such as
+// `[]Object<Repository>`
+func (t TypeRef) HumanName() string {
+	ref := &t
 
-	name := f.Type.Name
+	name := t.Name
 
 	wrap := func(s string) string {
 		return s
@@ -228,7 +316,7 @@ func (f Field) GetHumanTypeName() string {
 
 	for {
 		wrapFn := wrap
-		kind := t.Kind
+		kind := ref.Kind
 
 		if isEmptyKind(kind) {
 			wrap = func(s string) string {
@@ -249,37 +337,34 @@ func (f Field) GetHumanTypeName() string {
 			}
 		}
 
-		name = t.Name
+		name = ref.Name
 
-		if t.OfType == nil {
+		if ref.OfType == nil {
 			return wrap(name)
 		}
 
-		t = t.OfType
+		ref = ref.OfType
 	}
 }
 
-func isEmptyKind(kind string) bool {
-	for _, emptyKind := range emptyKinds {
-		if emptyKind == kind {
-			return true
-		}
+// isTruncated is whether the type reference ends in a wrapping type, because
+// it is nested deeper than an introspection query of the given depth asked for
+func (t *TypeRef) isTruncated(depth int) bool {
+	hops := 0
+	for ; t.OfType != nil; t = t.OfType {
+		hops++
 	}
 
-	return false
+	return hops >= depth && (t.Kind == "LIST" || t.Kind == "NON_NULL")
 }
 
-type inputValue struct {
-	Name         string
-	Description  string
-	Type         typeRef
-	DefaultValue string
-}
+// Nullable returns the type reference without its non-null wrapper
+func (t *TypeRef) Nullable() *TypeRef {
+	if t.Kind == "NON_NULL" && t.OfType != nil {
+		return t.OfType
+	}
 
-type typeRef struct {
-	Kind   string
-	Name   string
-	OfType *typeRef
+	return t
 }
 
 // 	Kind   string
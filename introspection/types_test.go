@@ -0,0 +1,194 @@
+package introspection
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	largeSchemaTypes  = 1500
+	largeSchemaFields = 20
+)
+
+var largeSchemaOnce sync.Once
+var largeSchemaResult *Schema
+
+// largeSchema builds a schema about the size of GitHub's, with objects that
+// each implement an interface and have fields of other objects
+func largeSchema(tb testing.TB) *Schema {
+	largeSchemaOnce.Do(func() {
+		var b strings.Builder
+
+		b.WriteString("interface Node { id: ID! }\n")
+		b.WriteString("type Query { node(id: ID!): Node, root: Type0 }\n")
+
+		for i := 0; i < largeSchemaTypes; i++ {
+			fmt.Fprintf(&b, "type Type%d implements Node {\n  id: ID!\n", i)
+			for j := 0; j < largeSchemaFields; j++ {
+				fmt.Fprintf(&b, "  field%d(first: Int, after: String): Type%d\n", j, (i*7+j+1)%largeSchemaTypes)
+			}
+			b.WriteString("}\n")
+		}
+
+		schema, err := ParseSDL(b.String())
+		if err != nil {
+			panic(err)
+		}
+
+		largeSchemaResult = schema
+	})
+
+	require.NotNil(tb, largeSchemaResult)
+	return largeSchemaResult
+}
+
+// unindexed copies a schema without the maps that index its types and
+// fields, so that lookups scan them instead
+func unindexed(schema *Schema) *Schema {
+	copied := *schema
+	copied.typeIndex = nil
+	copied.Types = make([]FullType, len(schema.Types))
+
+	for i, typ := range schema.Types {
+		typ.fieldIndex = nil
+		copied.Types[i] = typ
+	}
+
+	return &copied
+}
+
+// benchmarkSchemas runs a benchmark against the large schema with and
+// without its indexes
+func benchmarkSchemas(b *testing.B, benchmark func(b *testing.B, schema *Schema)) {
+	schema := largeSchema(b)
+
+	b.Run("Indexed", func(b *testing.B) {
+		benchmark(b, schema)
+	})
+
+	b.Run("Unindexed", func(b *testing.B) {
+		benchmark(b, unindexed(schema))
+	})
+}
+
+func TestLookupType(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	typ, ok := schema.LookupType("Repository")
+	require.True(t, ok)
+	assert.Equal(t, "Repository", typ.Name)
+
+	// Lookups return the schema's own types rather than copies
+	again, _ := schema.LookupType("Repository")
+	assert.True(t, typ == again)
+
+	field, ok := typ.GetField("issues")
+	require.True(t, ok)
+	assert.Equal(t, "issues", field.Name)
+
+	fieldAgain, _ := again.GetField("issues")
+	assert.True(t, field == fieldAgain)
+
+	_, ok = schema.LookupType("Nope")
+	assert.False(t, ok)
+
+	_, ok = typ.GetField("nope")
+	assert.False(t, ok)
+}
+
+func TestLookupTypeUnindexed(t *testing.T) {
+	t.Parallel()
+
+	// Schemas that were not loaded or parsed are searched instead
+	schema := unindexed(loadTestSchema(t))
+
+	typ, ok := schema.LookupType("Repository")
+	require.True(t, ok)
+	assert.Equal(t, "Repository", typ.Name)
+
+	field, ok := typ.GetField("issues")
+	require.True(t, ok)
+	assert.Equal(t, "issues", field.Name)
+
+	_, ok = schema.LookupType("Nope")
+	assert.False(t, ok)
+
+	_, ok = typ.GetField("nope")
+	assert.False(t, ok)
+}
+
+func TestSchemaIndexed(t *testing.T) {
+	t.Parallel()
+
+	sdl, err := ParseSDL("type Query { a: A } type A { b: Int }")
+	require.NoError(t, err)
+
+	file, err := LoadSchemaFile("testdata/schema.json")
+	require.NoError(t, err)
+
+	// Every way of loading a schema indexes it
+	for _, schema := range []*Schema{loadTestSchema(t), largeSchema(t), sdl, file} {
+		assert.Len(t, schema.typeIndex, len(schema.Types))
+
+		for _, typ := range schema.Types {
+			if len(typ.Fields) > 0 {
+				assert.Len(t, typ.fieldIndex, len(typ.Fields), typ.Name)
+			}
+		}
+	}
+}
+
+func BenchmarkLookupType(b *testing.B) {
+	benchmarkSchemas(b, func(b *testing.B, schema *Schema) {
+		names := make([]string, 0, len(schema.Types))
+		for _, typ := range schema.Types {
+			names = append(names, typ.Name)
+		}
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, ok := schema.LookupType(names[i%len(names)]); !ok {
+				b.Fatal("Missing type")
+			}
+		}
+	})
+}
+
+func BenchmarkGetField(b *testing.B) {
+	benchmarkSchemas(b, func(b *testing.B, schema *Schema) {
+		typ, _ := schema.LookupType("Type0")
+		name := fmt.Sprintf("field%d", largeSchemaFields-1)
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			if _, ok := typ.GetField(name); !ok {
+				b.Fatal("Missing field")
+			}
+		}
+	})
+}
+
+func BenchmarkGetFields(b *testing.B) {
+	query := querybuilder.NewRootQuery()
+	queries, err := querybuilder.ParsePath(".root.field19.field7(first: 10).field12.field3")
+	require.NoError(b, err)
+	query.AddChild(queries[0])
+
+	benchmarkSchemas(b, func(b *testing.B, schema *Schema) {
+		for i := 0; i < b.N; i++ {
+			if _, err := schema.GetFields(query); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
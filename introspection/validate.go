@@ -0,0 +1,537 @@
+package introspection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jclem/graphsh/querybuilder"
+)
+
+// ValidationError lists the problems found when validating a query
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, "\n")
+}
+
+// Validate checks a query and the selections in its lowest node against the
+// schema, reporting unknown fields, arguments, and types, arguments of the
+// wrong type, and missing, unexpected, or empty selections
+//
+// The values of the given variables are checked against the types they are
+// used as. Variables without values are not checked.
+func (s *Schema) Validate(query *querybuilder.Query, selections []*querybuilder.Selection, variables map[string]querybuilder.Value) error {
+	typ, err := s.GetRootType(query.Name)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{schema: s, variables: variables}
+
+	var field *Field
+	for _, node := range query.List() {
+		if field, typ = v.node(typ, node); typ == nil {
+			return v.err()
+		}
+	}
+
+	if field != nil {
+		v.subselection(field, typ, len(selections) > 0)
+	} else if len(selections) == 0 {
+		v.errorf("Selection on type %q must not be empty", typ.Name)
+	}
+
+	if isCompositeType(typ) {
+		v.selections(typ, selections)
+	}
+
+	return v.err()
+}
+
+// ValidatePath checks that a path, given as its first node, can be traversed
+// from the lowest node of a query
+//
+// Required arguments may be omitted, since they are checked when the path is
+// queried, but the path may not traverse into a field without subfields.
+func (s *Schema) ValidatePath(query *querybuilder.Query, path *querybuilder.Query) error {
+	typ, err := s.GetType(query)
+	if err != nil {
+		return err
+	}
+
+	v := &validator{schema: s, allowMissingArgs: true}
+
+	for node := path; node != nil; node = node.Child() {
+		field, fieldType := v.node(typ, node)
+		if fieldType == nil {
+			return v.err()
+		}
+
+		if !isCompositeType(fieldType) {
+			v.errorf("Can not traverse into field %q of type %s, which has no subfields", field.Name, field.Type)
+			return v.err()
+		}
+
+		typ = fieldType
+	}
+
+	return v.err()
+}
+
+// validator collects validation errors as it walks a query
+type validator struct {
+	schema *Schema
+	errors []string
+
+	// allowMissingArgs is whether required arguments may be omitted
+	allowMissingArgs bool
+
+	// variables are the values of variables, which are checked once for each
+	// type they are used as
+	variables map[string]querybuilder.Value
+	checked   map[string]bool
+}
+
+func (v *validator) errorf(format string, args ...interface{}) {
+	v.errors = append(v.errors, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Errors: v.errors}
+}
+
+// node validates a node of a query's path and returns its field and type,
+// with a nil type if it can not be resolved
+func (v *validator) node(typ *FullType, node *querybuilder.Query) (*Field, *FullType) {
+	field, ok := v.field(typ, node.Name)
+	if !ok {
+		return nil, nil
+	}
+
+	v.arguments(fmt.Sprintf("field %q", field.Name), node.Args, field.Args)
+
+	fieldType, ok := v.schema.lookupTypeOrMeta(field.GetTypeName())
+	if !ok {
+		v.errorf("Missing type %q", field.GetTypeName())
+		return nil, nil
+	}
+
+	if node.ConcreteType != "" {
+		return field, v.typeCondition(fieldType, node.ConcreteType)
+	}
+
+	return field, fieldType
+}
+
+func (v *validator) selections(typ *FullType, selections []*querybuilder.Selection) {
+	for _, selection := range selections {
+		for _, directive := range selection.Directives {
+			v.directive(directive)
+		}
+
+		if selection.IsFragment() {
+			fragmentType := typ
+			if selection.TypeCondition != "" {
+				fragmentType = v.typeCondition(typ, selection.TypeCondition)
+			}
+
+			if fragmentType == nil {
+				continue
+			}
+
+			if len(selection.Selections) == 0 {
+				v.errorf("Selection on type %q must not be empty", fragmentType.Name)
+			}
+
+			v.selections(fragmentType, selection.Selections)
+
+			continue
+		}
+
+		if selection.Name == "__typename" {
+			if len(selection.Selections) > 0 {
+				v.errorf("Field \"__typename\" of type String! can not have a selection of subfields")
+			}
+
+			continue
+		}
+
+		field, ok := v.schema.metaField(typ, selection.Name)
+		if !ok {
+			field, ok = v.field(typ, selection.Name)
+		}
+
+		if !ok {
+			continue
+		}
+
+		v.arguments(fmt.Sprintf("field %q", field.Name), selection.Args, field.Args)
+
+		fieldType, ok := v.schema.lookupTypeOrMeta(field.GetTypeName())
+		if !ok {
+			v.errorf("Missing type %q", field.GetTypeName())
+			continue
+		}
+
+		v.subselection(field, fieldType, len(selection.Selections) > 0)
+
+		if isCompositeType(fieldType) {
+			v.selections(fieldType, selection.Selections)
+		}
+	}
+}
+
+// subselection checks that a field has a selection of subfields exactly when
+// its type is an object, interface, or union
+func (v *validator) subselection(field *Field, typ *FullType, hasSelections bool) {
+	switch {
+	case isCompositeType(typ) && !hasSelections:
+		v.errorf("Field %q of type %s must have a selection of subfields", field.Name, field.Type)
+	case !isCompositeType(typ) && hasSelections:
+		v.errorf("Field %q of type %s can not have a selection of subfields", field.Name, field.Type)
+	}
+}
+
+func (v *validator) field(typ *FullType, name string) (*Field, bool) {
+	if field, ok := typ.GetField(name); ok {
+		return field, true
+	}
+
+	names := make([]string, 0, len(typ.Fields))
+	for _, f := range typ.Fields {
+		names = append(names, f.Name)
+	}
+
+	v.errorf("Unknown field %q on type %q%s", name, typ.Name, didYouMean(name, names))
+	return nil, false
+}
+
+// typeCondition validates a concrete type applied to a type and returns the
+// concrete type, or nil if it is invalid
+func (v *validator) typeCondition(parent *FullType, name string) *FullType {
+	typ, ok := v.schema.lookupTypeOrMeta(name)
+	if !ok {
+		var names []string
+		for _, t := range v.schema.Types {
+			if isCompositeType(&t) {
+				names = append(names, t.Name)
+			}
+		}
+
+		v.errorf("Unknown type %q%s", name, didYouMean(name, names))
+		return nil
+	}
+
+	if !isCompositeType(typ) {
+		v.errorf("Type %q is not an object, interface, or union type", name)
+		return nil
+	}
+
+	for _, possible := range possibleTypes(typ) {
+		for _, parentPossible := range possibleTypes(parent) {
+			if possible == parentPossible {
+				return typ
+			}
+		}
+	}
+
+	v.errorf("Type %q can never apply to type %q", name, parent.Name)
+	return nil
+}
+
+func (v *validator) directive(directive querybuilder.Directive) {
+	names := make([]string, 0, len(v.schema.Directives))
+	for _, d := range v.schema.Directives {
+		names = append(names, d.Name)
+
+		if d.Name == directive.Name {
+			v.arguments(fmt.Sprintf("directive @%s", d.Name), directive.Args, d.Args)
+			return
+		}
+	}
+
+	v.errorf("Unknown directive @%s%s", directive.Name, didYouMean(directive.Name, names))
+}
+
+// arguments validates the arguments passed to a field or directive
+func (v *validator) arguments(owner string, args map[string]interface{}, defs []InputValue) {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defNames := make([]string, 0, len(defs))
+	for _, def := range defs {
+		defNames = append(defNames, def.Name)
+	}
+
+	for _, name := range names {
+		def, ok := findInputValue(defs, name)
+		if !ok {
+			v.errorf("Unknown argument %q on %s%s", name, owner, didYouMean(name, defNames))
+			continue
+		}
+
+		v.value(fmt.Sprintf("Argument %q of %s", name, owner), querybuilder.ValueOf(args[name]), &def.Type)
+	}
+
+	for _, def := range defs {
+		if _, ok := args[def.Name]; !ok && def.IsRequired() && !v.allowMissingArgs {
+			v.errorf("Missing required argument %q of type %s on %s", def.Name, def.Type, owner)
+		}
+	}
+}
+
+// value validates a value passed where the given type is expected
+func (v *validator) value(context string, value querybuilder.Value, ref *TypeRef) {
+	if variable, ok := value.(querybuilder.Variable); ok {
+		v.variable(string(variable), ref)
+		return
+	}
+
+	if _, ok := value.(querybuilder.NullValue); ok {
+		if ref.Kind == "NON_NULL" {
+			v.errorf("%s expects %s, but got null", context, ref)
+		}
+
+		return
+	}
+
+	nullable := ref.Nullable()
+
+	if nullable.Kind == "LIST" && nullable.OfType != nil {
+		// A single value is accepted where a list is expected
+		if list, ok := value.(querybuilder.ListValue); ok {
+			for _, item := range list {
+				v.value(context, item, nullable.OfType)
+			}
+		} else {
+			v.value(context, value, nullable.OfType)
+		}
+
+		return
+	}
+
+	typ, ok := v.schema.lookupTypeOrMeta(nullable.Name)
+	if !ok {
+		return
+	}
+
+	switch typ.Kind {
+	case "SCALAR":
+		if !isScalarValue(typ.Name, value) {
+			v.errorf("%s expects %s, but got %s", context, ref, value)
+		}
+	case "ENUM":
+		enumValue, isEnum := value.(querybuilder.EnumValue)
+
+		names := make([]string, 0, len(typ.EnumValues))
+		for _, def := range typ.EnumValues {
+			if isEnum && def.Name == string(enumValue) {
+				return
+			}
+
+			names = append(names, def.Name)
+		}
+
+		suggestion := ""
+		if isEnum {
+			suggestion = didYouMean(string(enumValue), names)
+		}
+
+		v.errorf("%s expects %s, but got %s%s", context, ref, value, suggestion)
+	case "INPUT_OBJECT":
+		object, ok := value.(querybuilder.ObjectValue)
+		if !ok {
+			v.errorf("%s expects %s, but got %s", context, ref, value)
+			return
+		}
+
+		v.inputObject(typ, object)
+	}
+}
+
+// variable validates the value of a variable used where the given type is
+// expected, if it has a value
+func (v *validator) variable(name string, ref *TypeRef) {
+	value, ok := v.variables[name]
+	if !ok {
+		return
+	}
+
+	key := fmt.Sprintf("%s %s", name, ref)
+	if v.checked[key] {
+		return
+	}
+
+	if v.checked == nil {
+		v.checked = map[string]bool{}
+	}
+	v.checked[key] = true
+
+	v.value(fmt.Sprintf("Variable $%s", name), value, ref)
+}
+
+func (v *validator) inputObject(typ *FullType, object querybuilder.ObjectValue) {
+	names := make([]string, 0, len(typ.InputFields))
+	for _, def := range typ.InputFields {
+		names = append(names, def.Name)
+	}
+
+	given := map[string]bool{}
+
+	for _, field := range object {
+		given[field.Name] = true
+
+		def, ok := findInputValue(typ.InputFields, field.Name)
+		if !ok {
+			v.errorf("Unknown field %q on input type %q%s", field.Name, typ.Name, didYouMean(field.Name, names))
+			continue
+		}
+
+		v.value(fmt.Sprintf("Field %q of input type %q", field.Name, typ.Name), field.Value, &def.Type)
+	}
+
+	for _, def := range typ.InputFields {
+		if !given[def.Name] && def.IsRequired() {
+			v.errorf("Missing required field %q of type %s on input type %q", def.Name, def.Type, typ.Name)
+		}
+	}
+}
+
+// isScalarValue is whether a literal value is valid for a scalar type, with
+// custom scalars accepting any value
+func isScalarValue(scalar string, value querybuilder.Value) bool {
+	switch scalar {
+	case "Int":
+		_, ok := value.(querybuilder.IntValue)
+		return ok
+	case "Float":
+		switch value.(type) {
+		case querybuilder.IntValue, querybuilder.FloatValue:
+			return true
+		}
+		return false
+	case "String":
+		_, ok := value.(querybuilder.StringValue)
+		return ok
+	case "Boolean":
+		_, ok := value.(querybuilder.BooleanValue)
+		return ok
+	case "ID":
+		switch value.(type) {
+		case querybuilder.IntValue, querybuilder.StringValue:
+			return true
+		}
+		return false
+	}
+
+	return true
+}
+
+func findInputValue(defs []InputValue, name string) (InputValue, bool) {
+	for _, def := range defs {
+		if def.Name == name {
+			return def, true
+		}
+	}
+
+	return InputValue{}, false
+}
+
+func isCompositeType(typ *FullType) bool {
+	switch typ.Kind {
+	case "OBJECT", "INTERFACE", "UNION":
+		return true
+	}
+
+	return false
+}
+
+// possibleTypes gets the names of the object types a type may resolve to
+func possibleTypes(typ *FullType) []string {
+	if typ.Kind == "OBJECT" {
+		return []string{typ.Name}
+	}
+
+	names := make([]string, 0, len(typ.PossibleTypes))
+	for _, possible := range typ.PossibleTypes {
+		names = append(names, possible.Name)
+	}
+
+	return names
+}
+
+// didYouMean suggests the option closest to a misspelled name, if any option
+// is close enough
+func didYouMean(name string, options []string) string {
+	best := ""
+	bestDistance := len(name)/2 + 1
+
+	for _, option := range options {
+		var distance int
+		if strings.EqualFold(option, name) {
+			distance = 0
+		} else {
+			distance = levenshtein(strings.ToLower(name), strings.ToLower(option))
+		}
+
+		if distance < bestDistance {
+			best, bestDistance = option, distance
+		}
+	}
+
+	if best == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(", did you mean %q?", best)
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(br)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, value := range values[1:] {
+		if value < m {
+			m = value
+		}
+	}
+
+	return m
+}
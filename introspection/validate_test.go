@@ -0,0 +1,176 @@
+package introspection
+
+import (
+	"testing"
+
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validate(t *testing.T, schema *Schema, query *querybuilder.Query, selections string) error {
+	parsed, err := querybuilder.ParseSelections(selections)
+	require.NoError(t, err)
+
+	return schema.Validate(query, parsed, nil)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	repository := mustParsePath(t, `.repository(owner: "jclem", name: "graphsh")`)
+
+	for _, selections := range []string{
+		"name, owner { login, ... on User { bio } }",
+		"issues(first: 10, states: OPEN, orderBy: {field: CREATED_AT, direction: $direction}) { nodes { title } }",
+		"issues(states: [OPEN, CLOSED], labels: null) @include(if: true) { totalCount }",
+		"object(oid: 123) { __typename ... on Blob { text } }",
+	} {
+		assert.NoError(t, validate(t, schema, repository, selections), selections)
+	}
+
+	assert.NoError(t, validate(t, schema, querybuilder.NewRootQuery(), `viewer { login } search(query: "graphsh", type: REPOSITORY) { issueCount }`))
+
+	// The query root type has the introspection meta-fields
+	for _, selections := range []string{
+		"__schema { types { name } queryType { name } directives { name isRepeatable } }",
+		`__type(name: "Repository") { name kind fields(includeDeprecated: true) { name type { ofType { name } } } }`,
+	} {
+		assert.NoError(t, validate(t, schema, querybuilder.NewRootQuery(), selections), selections)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	repository := mustParsePath(t, `.repository(owner: "jclem", name: "graphsh")`)
+
+	tests := []struct {
+		selections string
+		errors     []string
+	}{
+		{"nmae", []string{`Unknown field "nmae" on type "Repository", did you mean "name"?`}},
+		{"name { length }", []string{`Field "name" of type String! can not have a selection of subfields`}},
+		{"owner", []string{`Field "owner" of type RepositoryOwner! must have a selection of subfields`}},
+		{"issues(frist: 10) { totalCount }", []string{`Unknown argument "frist" on field "issues", did you mean "first"?`}},
+		{`issues(first: "ten") { totalCount }`, []string{`Argument "first" of field "issues" expects Int, but got "ten"`}},
+		{"issues(states: [OPN]) { totalCount }", []string{`Argument "states" of field "issues" expects IssueState!, but got OPN, did you mean "OPEN"?`}},
+		{"issues(orderBy: {feld: CREATED_AT}) { totalCount }", []string{
+			`Unknown field "feld" on input type "IssueOrder", did you mean "field"?`,
+			`Missing required field "field" of type IssueOrderField! on input type "IssueOrder"`,
+		}},
+		{"owner { ... on Blob { text } }", []string{`Type "Blob" can never apply to type "RepositoryOwner"`}},
+		{"owner { ... on Usr { bio } }", []string{`Unknown type "Usr", did you mean "User"?`}},
+		{"name @skip", []string{`Missing required argument "if" of type Boolean! on directive @skip`}},
+		{"name @inclde(if: true)", []string{`Unknown directive @inclde, did you mean "include"?`}},
+		{"owner { ... on User { } }", []string{`Selection on type "User" must not be empty`}},
+	}
+
+	for _, test := range tests {
+		err := validate(t, schema, repository, test.selections)
+		if assert.IsType(t, &ValidationError{}, err, test.selections) {
+			assert.Equal(t, test.errors, err.(*ValidationError).Errors, test.selections)
+		}
+	}
+
+	err := validate(t, schema, querybuilder.NewRootQuery(), "__schema { typs { name } } __type { kind { name } }")
+	assert.EqualError(t, err, `Unknown field "typs" on type "__Schema", did you mean "types"?`+"\n"+
+		`Missing required argument "name" of type String! on field "__type"`+"\n"+
+		`Field "kind" of type __TypeKind! can not have a selection of subfields`)
+
+	err = validate(t, schema, repository, "__schema { types { name } }")
+	assert.EqualError(t, err, `Unknown field "__schema" on type "Repository"`)
+
+	err = validate(t, schema, querybuilder.NewRootQuery(), " ")
+	assert.EqualError(t, err, `Selection on type "Query" must not be empty`)
+}
+
+func TestValidateVariables(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	repository := mustParsePath(t, `.repository(owner: $owner, name: "graphsh")`)
+
+	validateVariables := func(selections string, variables map[string]querybuilder.Value) error {
+		parsed, err := querybuilder.ParseSelections(selections)
+		require.NoError(t, err)
+
+		return schema.Validate(repository, parsed, variables)
+	}
+
+	variables := map[string]querybuilder.Value{
+		"owner": querybuilder.StringValue("jclem"),
+		"first": querybuilder.IntValue(10),
+		"order": querybuilder.ObjectValue{{Name: "field", Value: querybuilder.EnumValue("CREATED_AT")}},
+		"state": querybuilder.EnumValue("OPEN"),
+	}
+
+	// Variables without values are not checked
+	assert.NoError(t, validateVariables("issues(first: $first, labels: $labels, orderBy: $order, states: $state) { totalCount }", variables))
+
+	variables = map[string]querybuilder.Value{
+		"owner": querybuilder.IntValue(1),
+		"first": querybuilder.StringValue("ten"),
+		"order": querybuilder.ObjectValue{{Name: "field", Value: querybuilder.EnumValue("CREATED")}},
+		"label": querybuilder.NullValue{},
+	}
+
+	// Each variable is checked once for each type it is used as
+	err := validateVariables("issues(first: $first, orderBy: $order, labels: [$label]) { totalCount } issues(first: $first) { totalCount }", variables)
+	assert.EqualError(t, err, `Variable $owner expects String!, but got 1`+"\n"+
+		`Variable $first expects Int, but got "ten"`+"\n"+
+		`Variable $label expects String!, but got null`+"\n"+
+		`Field "field" of input type "IssueOrder" expects IssueOrderField!, but got CREATED, did you mean "CREATED_AT"?`)
+}
+
+func TestValidatePath(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	err := validate(t, schema, mustParsePath(t, `.repository(owner: "jclem").nmae`), "length")
+	assert.EqualError(t, err, "Missing required argument \"name\" of type String! on field \"repository\"\n"+
+		`Unknown field "nmae" on type "Repository", did you mean "name"?`)
+
+	err = validate(t, schema, mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").name`), "length")
+	assert.EqualError(t, err, `Field "name" of type String! can not have a selection of subfields`)
+
+	query := mustParsePath(t, `.repository(owner: "jclem", name: "graphsh").owner`)
+	query.Child().Child().ConcreteType = "Issue"
+	err = validate(t, schema, query, "title")
+	assert.EqualError(t, err, `Type "Issue" can never apply to type "RepositoryOwner"`)
+}
+
+func TestValidateTraversal(t *testing.T) {
+	t.Parallel()
+
+	schema := loadTestSchema(t)
+
+	root := querybuilder.NewRootQuery()
+	path := func(input string) *querybuilder.Query {
+		queries, err := querybuilder.ParsePath(input)
+		require.NoError(t, err)
+		return queries[0]
+	}
+
+	assert.NoError(t, schema.ValidatePath(root, path(".repository.issues(first: 10).nodes")))
+	assert.NoError(t, schema.ValidatePath(mustParsePath(t, ".viewer"), path(".repositories")))
+
+	assert.EqualError(t, schema.ValidatePath(root, path(".repository.isues")), `Unknown field "isues" on type "Repository", did you mean "issues"?`)
+	assert.EqualError(t, schema.ValidatePath(root, path(".repository(ownr: \"jclem\")")), `Unknown argument "ownr" on field "repository", did you mean "owner"?`)
+	assert.EqualError(t, schema.ValidatePath(root, path(".repository(owner: 1)")), `Argument "owner" of field "repository" expects String!, but got 1`)
+	assert.EqualError(t, schema.ValidatePath(root, path(".repository.name.length")), `Can not traverse into field "name" of type String!, which has no subfields`)
+	assert.EqualError(t, schema.ValidatePath(mustParsePath(t, ".viewer"), path(".login")), `Can not traverse into field "login" of type String!, which has no subfields`)
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("name", "name"))
+	assert.Equal(t, 2, levenshtein("nmae", "name"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "name"))
+}
@@ -0,0 +1,192 @@
+package introspection
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jclem/graphsh/querybuilder"
+)
+
+// GetVariableDefinitions finds every variable referenced in a query and the
+// selections in its lowest node, and infers each variable's type from the
+// argument it is passed to
+func (s *Schema) GetVariableDefinitions(query *querybuilder.Query, selections []*querybuilder.Selection) ([]querybuilder.VariableDefinition, error) {
+	typ, err := s.GetRootType(query.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := map[string]string{}
+
+	for _, node := range query.List() {
+		field, ok := typ.GetField(node.Name)
+		if !ok {
+			return nil, fmt.Errorf("Missing field %q from type %q", node.Name, typ.Name)
+		}
+
+		if err := s.collectArgVariables(node.Args, field.Args, defs); err != nil {
+			return nil, err
+		}
+
+		typ, ok = s.LookupType(field.GetTypeName())
+		if !ok {
+			return nil, fmt.Errorf("Missing type %q", field.GetTypeName())
+		}
+
+		if node.ConcreteType != "" {
+			typ, ok = s.LookupType(node.ConcreteType)
+			if !ok {
+				return nil, fmt.Errorf("Missing type %q", node.ConcreteType)
+			}
+		}
+	}
+
+	if err := s.collectSelectionVariables(typ, selections, defs); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	definitions := make([]querybuilder.VariableDefinition, 0, len(names))
+	for _, name := range names {
+		definitions = append(definitions, querybuilder.VariableDefinition{Name: name, Type: defs[name]})
+	}
+
+	return definitions, nil
+}
+
+// collectSelectionVariables collects variables from selections on a type,
+// which is nil when the type could not be determined
+func (s *Schema) collectSelectionVariables(typ *FullType, selections []*querybuilder.Selection, defs map[string]string) error {
+	for _, selection := range selections {
+		for _, directive := range selection.Directives {
+			if err := s.collectArgVariables(directive.Args, s.getDirectiveArgs(directive.Name), defs); err != nil {
+				return err
+			}
+		}
+
+		if selection.IsFragment() {
+			fragmentType := typ
+			if selection.TypeCondition != "" {
+				fragmentType, _ = s.lookupTypeOrMeta(selection.TypeCondition)
+			}
+
+			if err := s.collectSelectionVariables(fragmentType, selection.Selections, defs); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		var args []InputValue
+		var fieldType *FullType
+
+		if typ != nil {
+			field, ok := s.metaField(typ, selection.Name)
+			if !ok {
+				field, ok = typ.GetField(selection.Name)
+			}
+
+			if ok {
+				args = field.Args
+				fieldType, _ = s.lookupTypeOrMeta(field.GetTypeName())
+			}
+		}
+
+		if err := s.collectArgVariables(selection.Args, args, defs); err != nil {
+			return err
+		}
+
+		if err := s.collectSelectionVariables(fieldType, selection.Selections, defs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) collectArgVariables(args map[string]interface{}, argDefs []InputValue, defs map[string]string) error {
+	for name, arg := range args {
+		var ref *TypeRef
+
+		for i := range argDefs {
+			if argDefs[i].Name == name {
+				ref = &argDefs[i].Type
+				break
+			}
+		}
+
+		if err := s.collectValueVariables(querybuilder.ValueOf(arg), ref, defs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectValueVariables collects variables in a value passed where the given
+// type is expected, which is nil when the type could not be determined
+func (s *Schema) collectValueVariables(value querybuilder.Value, ref *TypeRef, defs map[string]string) error {
+	switch v := value.(type) {
+	case querybuilder.Variable:
+		if ref == nil {
+			return fmt.Errorf("Can not infer the type of variable %s", v)
+		}
+
+		return addVariableDefinition(string(v), ref.String(), defs)
+	case querybuilder.ListValue:
+		var itemRef *TypeRef
+		if ref != nil && ref.Nullable().Kind == "LIST" {
+			itemRef = ref.Nullable().OfType
+		}
+
+		for _, item := range v {
+			if err := s.collectValueVariables(item, itemRef, defs); err != nil {
+				return err
+			}
+		}
+	case querybuilder.ObjectValue:
+		var inputFields []InputValue
+		if ref != nil {
+			if inputType, ok := s.LookupType(ref.Nullable().Name); ok {
+				inputFields = inputType.InputFields
+			}
+		}
+
+		for _, field := range v {
+			if err := s.collectArgVariables(map[string]interface{}{field.Name: field.Value}, inputFields, defs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addVariableDefinition records a variable's type, preferring the non-null
+// type when a variable is used in both nullable and non-null positions
+func addVariableDefinition(name string, typ string, defs map[string]string) error {
+	existing, ok := defs[name]
+
+	switch {
+	case !ok || existing == typ || existing+"!" == typ:
+		defs[name] = typ
+	case typ+"!" == existing:
+	default:
+		return fmt.Errorf("Variable $%s is used as both %s and %s", name, existing, typ)
+	}
+
+	return nil
+}
+
+func (s *Schema) getDirectiveArgs(name string) []InputValue {
+	if directive, ok := s.getDirective(name); ok {
+		return directive.Args
+	}
+
+	return nil
+}
@@ -0,0 +1,522 @@
+package lexer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Kind is the kind of a lexical token
+type Kind int
+
+// Token kinds produced by the lexer
+const (
+	EOF Kind = iota
+	Bang
+	Dollar
+	Amp
+	ParenL
+	ParenR
+	Spread
+	Dot
+	Colon
+	Equals
+	At
+	BracketL
+	BracketR
+	BraceL
+	Pipe
+	BraceR
+	Name
+	Int
+	Float
+	String
+	BlockString
+)
+
+var kindNames = map[Kind]string{
+	EOF:         "end of input",
+	Bang:        `"!"`,
+	Dollar:      `"$"`,
+	Amp:         `"&"`,
+	ParenL:      `"("`,
+	ParenR:      `")"`,
+	Spread:      `"..."`,
+	Dot:         `"."`,
+	Colon:       `":"`,
+	Equals:      `"="`,
+	At:          `"@"`,
+	BracketL:    `"["`,
+	BracketR:    `"]"`,
+	BraceL:      `"{"`,
+	Pipe:        `"|"`,
+	BraceR:      `"}"`,
+	Name:        "name",
+	Int:         "int",
+	Float:       "float",
+	String:      "string",
+	BlockString: "block string",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+var punctuators = map[rune]Kind{
+	'!': Bang,
+	'$': Dollar,
+	'&': Amp,
+	'(': ParenL,
+	')': ParenR,
+	':': Colon,
+	'=': Equals,
+	'@': At,
+	'[': BracketL,
+	']': BracketR,
+	'{': BraceL,
+	'|': Pipe,
+	'}': BraceR,
+}
+
+// Token is a single lexical token
+type Token struct {
+	Kind Kind
+
+	// Value is the token's value—for strings this is the unescaped value, and
+	// for other tokens it is the raw source text
+	Value string
+
+	// Start and End are the 1-based columns the token spans in the input
+	Start int
+	End   int
+}
+
+func (t Token) String() string {
+	switch t.Kind {
+	case EOF:
+		return t.Kind.String()
+	case Name, Int, Float:
+		return fmt.Sprintf("%s %q", t.Kind, t.Value)
+	case String, BlockString:
+		return fmt.Sprintf("%s %s", t.Kind, strconv.Quote(t.Value))
+	default:
+		return t.Kind.String()
+	}
+}
+
+// Error is a syntax error at a column of the input
+type Error struct {
+	Column  int
+	Message string
+}
+
+// Errorf creates a new syntax error at the given column
+func Errorf(column int, format string, args ...interface{}) *Error {
+	return &Error{Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Syntax error at column %d: %s", e.Column, e.Message)
+}
+
+// Lexer tokenizes GraphQL source text
+//
+// Commas, whitespace, line terminators and comments are insignificant and are
+// skipped, as in GraphQL. Unlike GraphQL, a lone "." is a valid token, since
+// it separates segments in graphsh paths.
+type Lexer struct {
+	src    []rune
+	pos    int
+	peeked *Token
+}
+
+// New creates a new Lexer for the given input
+func New(input string) *Lexer {
+	return &Lexer{src: []rune(input)}
+}
+
+// Peek returns the next token without consuming it
+func (l *Lexer) Peek() (Token, error) {
+	if l.peeked != nil {
+		return *l.peeked, nil
+	}
+
+	tok, err := l.lex()
+	if err != nil {
+		return tok, err
+	}
+
+	l.peeked = &tok
+	return tok, nil
+}
+
+// Next consumes and returns the next token
+func (l *Lexer) Next() (Token, error) {
+	if l.peeked != nil {
+		tok := *l.peeked
+		l.peeked = nil
+		return tok, nil
+	}
+
+	return l.lex()
+}
+
+// Tokenize returns every token in the input, excluding the final EOF
+func Tokenize(input string) ([]Token, error) {
+	l := New(input)
+
+	var tokens []Token
+
+	for {
+		tok, err := l.Next()
+		if err != nil {
+			return tokens, err
+		}
+
+		if tok.Kind == EOF {
+			return tokens, nil
+		}
+
+		tokens = append(tokens, tok)
+	}
+}
+
+func (l *Lexer) lex() (Token, error) {
+	l.skipIgnored()
+
+	start := l.pos
+
+	if l.pos >= len(l.src) {
+		return l.token(EOF, start, ""), nil
+	}
+
+	r := l.src[l.pos]
+
+	if kind, ok := punctuators[r]; ok {
+		l.pos++
+		return l.token(kind, start, string(r)), nil
+	}
+
+	switch {
+	case r == '.':
+		if l.hasPrefix("...") {
+			l.pos += 3
+			return l.token(Spread, start, "..."), nil
+		}
+
+		l.pos++
+		return l.token(Dot, start, "."), nil
+	case isNameStart(r):
+		for l.pos < len(l.src) && isNameContinue(l.src[l.pos]) {
+			l.pos++
+		}
+
+		return l.token(Name, start, string(l.src[start:l.pos])), nil
+	case r == '-' || isDigit(r):
+		return l.lexNumber()
+	case r == '"':
+		if l.hasPrefix(`"""`) {
+			return l.lexBlockString()
+		}
+
+		return l.lexString()
+	}
+
+	return Token{}, Errorf(start+1, "Unexpected character %q", r)
+}
+
+func (l *Lexer) token(kind Kind, start int, value string) Token {
+	return Token{Kind: kind, Value: value, Start: start + 1, End: l.pos + 1}
+}
+
+func (l *Lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch r := l.src[l.pos]; {
+		case r == ' ' || r == '\t' || r == ',' || r == '\n' || r == '\r' || r == '\ufeff':
+			l.pos++
+		case r == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// hasPrefix is whether the input at the current position starts with a
+// prefix, which is compared rune by rune so that the input is not copied
+func (l *Lexer) hasPrefix(prefix string) bool {
+	i := l.pos
+	for _, r := range prefix {
+		if i >= len(l.src) || l.src[i] != r {
+			return false
+		}
+
+		i++
+	}
+
+	return true
+}
+
+func (l *Lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+
+	return l.src[l.pos+offset]
+}
+
+func (l *Lexer) lexNumber() (Token, error) {
+	start := l.pos
+	kind := Int
+
+	if l.peekRune(0) == '-' {
+		l.pos++
+	}
+
+	if l.peekRune(0) == '0' {
+		l.pos++
+
+		if isDigit(l.peekRune(0)) {
+			return Token{}, Errorf(l.pos+1, "Invalid number, unexpected digit after 0: %q", l.peekRune(0))
+		}
+	} else if err := l.readDigits(); err != nil {
+		return Token{}, err
+	}
+
+	if l.peekRune(0) == '.' && isDigit(l.peekRune(1)) {
+		kind = Float
+		l.pos++
+
+		if err := l.readDigits(); err != nil {
+			return Token{}, err
+		}
+	}
+
+	if r := l.peekRune(0); r == 'e' || r == 'E' {
+		kind = Float
+		l.pos++
+
+		if r := l.peekRune(0); r == '+' || r == '-' {
+			l.pos++
+		}
+
+		if err := l.readDigits(); err != nil {
+			return Token{}, err
+		}
+	}
+
+	if r := l.peekRune(0); isNameStart(r) {
+		return Token{}, Errorf(l.pos+1, "Invalid number, expected digit but got %q", r)
+	}
+
+	return l.token(kind, start, string(l.src[start:l.pos])), nil
+}
+
+func (l *Lexer) readDigits() error {
+	if !isDigit(l.peekRune(0)) {
+		if l.pos >= len(l.src) {
+			return Errorf(l.pos+1, "Invalid number, expected digit but got end of input")
+		}
+
+		return Errorf(l.pos+1, "Invalid number, expected digit but got %q", l.peekRune(0))
+	}
+
+	for isDigit(l.peekRune(0)) {
+		l.pos++
+	}
+
+	return nil
+}
+
+func (l *Lexer) lexString() (Token, error) {
+	start := l.pos
+	l.pos++
+
+	var value strings.Builder
+
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+
+		switch {
+		case r == '"':
+			l.pos++
+			return l.token(String, start, value.String()), nil
+		case r == '\n' || r == '\r':
+			return Token{}, Errorf(l.pos+1, "Unterminated string")
+		case r == '\\':
+			escaped, err := l.readEscape()
+			if err != nil {
+				return Token{}, err
+			}
+			value.WriteRune(escaped)
+		default:
+			value.WriteRune(r)
+			l.pos++
+		}
+	}
+
+	return Token{}, Errorf(l.pos+1, "Unterminated string")
+}
+
+var simpleEscapes = map[rune]rune{
+	'"':  '"',
+	'\\': '\\',
+	'/':  '/',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+}
+
+func (l *Lexer) readEscape() (rune, error) {
+	escapeStart := l.pos
+	l.pos++
+
+	r := l.peekRune(0)
+	if escaped, ok := simpleEscapes[r]; ok {
+		l.pos++
+		return escaped, nil
+	}
+
+	if r != 'u' {
+		return 0, Errorf(escapeStart+1, "Invalid character escape sequence \\%c", r)
+	}
+
+	l.pos++
+
+	code, err := l.readHex(escapeStart)
+	if err != nil {
+		return 0, err
+	}
+
+	// Combine UTF-16 surrogate pairs written as two consecutive escapes
+	if utf16.IsSurrogate(code) && l.hasPrefix(`\u`) {
+		pairStart := l.pos
+		l.pos += 2
+
+		low, err := l.readHex(pairStart)
+		if err != nil {
+			return 0, err
+		}
+
+		if decoded := utf16.DecodeRune(code, low); decoded != unicode.ReplacementChar {
+			return decoded, nil
+		}
+
+		return 0, Errorf(escapeStart+1, "Invalid Unicode surrogate pair")
+	}
+
+	return code, nil
+}
+
+func (l *Lexer) readHex(escapeStart int) (rune, error) {
+	if l.pos+4 > len(l.src) {
+		return 0, Errorf(escapeStart+1, "Invalid Unicode escape sequence")
+	}
+
+	code, err := strconv.ParseUint(string(l.src[l.pos:l.pos+4]), 16, 32)
+	if err != nil {
+		return 0, Errorf(escapeStart+1, "Invalid Unicode escape sequence \\u%s", string(l.src[l.pos:l.pos+4]))
+	}
+
+	l.pos += 4
+
+	return rune(code), nil
+}
+
+func (l *Lexer) lexBlockString() (Token, error) {
+	start := l.pos
+	l.pos += 3
+
+	var raw strings.Builder
+
+	for l.pos < len(l.src) {
+		switch {
+		case l.hasPrefix(`"""`):
+			l.pos += 3
+			return l.token(BlockString, start, BlockStringValue(raw.String())), nil
+		case l.hasPrefix(`\"""`):
+			raw.WriteString(`"""`)
+			l.pos += 4
+		default:
+			raw.WriteRune(l.src[l.pos])
+			l.pos++
+		}
+	}
+
+	return Token{}, Errorf(l.pos+1, "Unterminated block string")
+}
+
+// BlockStringValue applies the GraphQL block string algorithm to raw block
+// string contents, removing common indentation and leading and trailing
+// blank lines
+func BlockStringValue(raw string) string {
+	lines := splitLines(raw)
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		indent := leadingWhitespace(line)
+		if indent == len(line) {
+			continue
+		}
+
+		if commonIndent == -1 || indent < commonIndent {
+			commonIndent = indent
+		}
+	}
+
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= commonIndent {
+				lines[i] = lines[i][commonIndent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+
+	for len(lines) > 0 && isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func splitLines(s string) []string {
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = strings.Replace(s, "\r", "\n", -1)
+	return strings.Split(s, "\n")
+}
+
+func leadingWhitespace(s string) int {
+	i := 0
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isBlank(s string) bool {
+	return leadingWhitespace(s) == len(s)
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+}
+
+func isNameContinue(r rune) bool {
+	return isNameStart(r) || isDigit(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,98 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func kinds(tokens []Token) []Kind {
+	var k []Kind
+	for _, tok := range tokens {
+		k = append(k, tok.Kind)
+	}
+	return k
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := Tokenize(`.repository(owner: "jclem", name: "graphsh").object`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Kind{Dot, Name, ParenL, Name, Colon, String, Name, Colon, String, ParenR, Dot, Name}, kinds(tokens))
+	assert.Equal(t, 1, tokens[0].Start)
+	assert.Equal(t, 2, tokens[1].Start)
+	assert.Equal(t, 12, tokens[1].End)
+
+	tokens, err = Tokenize("... on Foo # a comment\n{ a }")
+	assert.NoError(t, err)
+	assert.Equal(t, []Kind{Spread, Name, Name, BraceL, Name, BraceR}, kinds(tokens))
+}
+
+func TestTokenizeNumbers(t *testing.T) {
+	tokens, err := Tokenize("0 -12 1.5 -0.25 1e10 6.02E+23")
+	assert.NoError(t, err)
+	assert.Equal(t, []Kind{Int, Int, Float, Float, Float, Float}, kinds(tokens))
+	assert.Equal(t, "-0.25", tokens[3].Value)
+
+	_, err = Tokenize("01")
+	assert.EqualError(t, err, `Syntax error at column 2: Invalid number, unexpected digit after 0: '1'`)
+
+	_, err = Tokenize("1.")
+	assert.NoError(t, err, "a trailing dot is a separate token")
+
+	_, err = Tokenize("1e")
+	assert.EqualError(t, err, "Syntax error at column 3: Invalid number, expected digit but got end of input")
+
+	_, err = Tokenize("12abc")
+	assert.EqualError(t, err, `Syntax error at column 3: Invalid number, expected digit but got 'a'`)
+}
+
+func TestTokenizeStrings(t *testing.T) {
+	tokens, err := Tokenize(`"main:README.md" "a, b" "tab\there \"quoted\" \u00e9 \ud83d\ude00"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "main:README.md", tokens[0].Value)
+	assert.Equal(t, "a, b", tokens[1].Value)
+	assert.Equal(t, "tab\there \"quoted\" é 😀", tokens[2].Value)
+
+	_, err = Tokenize(`"abc`)
+	assert.EqualError(t, err, "Syntax error at column 5: Unterminated string")
+
+	_, err = Tokenize(`"\q"`)
+	assert.EqualError(t, err, `Syntax error at column 2: Invalid character escape sequence \q`)
+
+	_, err = Tokenize(`"\u12zz"`)
+	assert.EqualError(t, err, `Syntax error at column 2: Invalid Unicode escape sequence \u12zz`)
+
+	_, err = Tokenize(`"\u12`)
+	assert.EqualError(t, err, "Syntax error at column 2: Invalid Unicode escape sequence")
+}
+
+func TestTokenizeBlockStrings(t *testing.T) {
+	tokens, err := Tokenize(`"""
+    Hello,
+      World!
+
+    Escaped \""" quotes
+  """`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Kind{BlockString}, kinds(tokens))
+	assert.Equal(t, "Hello,\n  World!\n\nEscaped \"\"\" quotes", tokens[0].Value)
+
+	_, err = Tokenize(`"""abc`)
+	assert.EqualError(t, err, "Syntax error at column 7: Unterminated block string")
+}
+
+func TestTokenizeUnexpectedCharacter(t *testing.T) {
+	_, err := Tokenize(".foo(a: ?)")
+	assert.EqualError(t, err, `Syntax error at column 9: Unexpected character '?'`)
+}
+
+func BenchmarkTokenizeBlockString(b *testing.B) {
+	input := `"""` + strings.Repeat("A description of a type. ", 4000) + `"""`
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Tokenize(input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,15 +1,35 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/chzyer/readline"
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/schemadiff"
 	"github.com/jclem/graphsh/session"
 	flag "github.com/spf13/pflag"
 )
 
 var headers = flag.StringArrayP("header", "H", []string{}, "Set a custom request header")
 var help = flag.BoolP("help", "h", false, "Print this help message")
+var schemaFile = flag.String("schema", "", "Load the schema from an introspection result or SDL file instead of introspecting the endpoint")
+var noCache = flag.Bool("no-cache", false, "Always introspect the schema instead of using the schema cache")
+var schemaTTL = flag.Duration("schema-ttl", session.DefaultSchemaTTL, "Set how long a cached schema is used before it is introspected again")
+var subscriptionEndpoint = flag.String("subscription-endpoint", "", "Set the WebSocket endpoint for subscriptions, which defaults to the endpoint")
+var commands = flag.StringArrayP("command", "c", []string{}, "Execute a command and exit, may be given multiple times")
+var file = flag.StringP("file", "f", "", "Execute commands from a script file, or \"-\" for stdin, and exit")
+var keepGoing = flag.Bool("keep-going", false, "Continue executing commands after one fails")
+var assumeYes = flag.BoolP("yes", "y", false, "Execute mutations without asking for confirmation")
+var prompt = flag.String("prompt", session.DefaultPrompt, "Set the prompt template, which may use {{.Path}}, {{.Type}}, {{.Host}}, {{.Operation}}, and {{truncate n .Path}}")
+var sdl = flag.Bool("sdl", false, "With the schema subcommand, print the schema in the GraphQL schema definition language")
+var jsonOutput = flag.Bool("json", false, "With the diff subcommand, print the changes as JSON")
+var historySkipSecrets = flag.Bool("history-skip-secrets", false, "Do not save commands that look like they contain secrets to the history file")
 
 func main() {
 	flag.Parse()
@@ -21,19 +41,193 @@ func main() {
 		os.Exit(0)
 	}
 
+	isSchema := flag.Arg(0) == "schema"
+	isDiff := flag.Arg(0) == "diff"
+
 	endpoint := flag.Arg(0)
-	if endpoint == "" {
+	if isSchema {
+		endpoint = flag.Arg(1)
+	}
+
+	if endpoint == "" || (isDiff && flag.NArg() != 3) {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if len(*commands) > 0 && *file != "" {
+		fmt.Fprintln(os.Stderr, "Only one of --command and --file may be given")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	session.Loop(session.Options{
+	options := session.Options{
 		Endpoint: endpoint,
 		Headers:  *headers,
-	})
+		Prompt:   *prompt,
+
+		SchemaFile:           *schemaFile,
+		NoCache:              *noCache,
+		SchemaTTL:            *schemaTTL,
+		SubscriptionEndpoint: *subscriptionEndpoint,
+
+		AssumeYes:          *assumeYes,
+		HistorySkipSecrets: *historySkipSecrets,
+	}
+
+	if isSchema {
+		os.Exit(runSchema(options))
+	}
+
+	if isDiff {
+		os.Exit(runDiff(flag.Arg(1), flag.Arg(2), options))
+	}
+
+	os.Exit(run(options))
+}
+
+func run(options session.Options) int {
+	s, err := session.NewSession(options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch {
+	case len(*commands) > 0:
+		err = s.RunCommands(*commands, *keepGoing)
+	case *file != "" || !readline.IsTerminal(int(os.Stdin.Fd())):
+		err = runScript(s)
+	default:
+		err = s.Loop()
+	}
+
+	if err != nil {
+		if err != session.ErrCommandFailed {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		return 1
+	}
+
+	return 0
+}
+
+// runSchema prints the endpoint's schema, which is always introspected again
+// instead of using the schema cache, or else the schema file's
+func runSchema(options session.Options) int {
+	if !*sdl {
+		fmt.Fprintln(os.Stderr, "The schema subcommand requires --sdl, which is the only supported format")
+		return 1
+	}
+
+	var schema *introspection.Schema
+	var err error
+
+	if options.SchemaFile != "" {
+		schema, err = introspection.LoadSchemaFile(options.SchemaFile)
+	} else {
+		schema, err = introspectSchema(options.Endpoint, options)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Print(introspection.PrintSchema(schema))
+
+	return 0
+}
+
+// runDiff prints the changes from an old schema to a new one, each of which
+// is an endpoint or a schema file
+func runDiff(old string, new string, options session.Options) int {
+	oldSchema, err := loadDiffSchema(old, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	newSchema, err := loadDiffSchema(new, options)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	changes := schemadiff.Diff(oldSchema, newSchema)
+
+	if *jsonOutput {
+		if changes == nil {
+			changes = []schemadiff.Change{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		return 0
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	for _, change := range changes {
+		fmt.Fprintf(tw, "%s\t%s\n", change.Criticality, change.Message)
+	}
+	tw.Flush()
+
+	return 0
+}
+
+// loadDiffSchema loads a schema by introspecting an endpoint, which is always
+// done again instead of using the schema cache, or else from a file
+func loadDiffSchema(source string, options session.Options) (*introspection.Schema, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return introspection.LoadSchemaFile(source)
+	}
+
+	return introspectSchema(source, options)
+}
+
+// introspectSchema introspects an endpoint's schema with the given headers,
+// without using the schema cache
+func introspectSchema(endpoint string, options session.Options) (*introspection.Schema, error) {
+	headers, err := session.ParseHeaders(options.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return introspection.LoadSchema(graphql.New(endpoint, headers))
+}
+
+func runScript(s *session.Session) error {
+	name := *file
+	var script io.Reader = os.Stdin
+
+	if name == "" || name == "-" {
+		name = "<stdin>"
+	} else {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		script = f
+	}
+
+	return s.RunScript(name, script, *keepGoing)
 }
 
 func usage() {
 	fmt.Fprintln(os.Stderr, "Usage: graphsh <endpoint> [<options>]")
+	fmt.Fprintln(os.Stderr, "       graphsh schema --sdl <endpoint> [<options>]")
+	fmt.Fprintln(os.Stderr, "       graphsh diff <old> <new> [<options>]")
 	flag.PrintDefaults()
 }
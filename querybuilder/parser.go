@@ -0,0 +1,251 @@
+package querybuilder
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/jclem/graphsh/lexer"
+)
+
+// ParsePath parses a traversal path such as `.repository(owner: "jclem").owner`
+// into a list of queries, each of which is the child of the one before it
+func ParsePath(input string) ([]*Query, error) {
+	p := parser{lexer: lexer.New(input)}
+
+	var queries []*Query
+
+	for {
+		tok, err := p.lexer.Peek()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok.Kind == lexer.EOF {
+			if len(queries) == 0 {
+				return nil, lexer.Errorf(tok.Start, "Expected %s, found %s", lexer.Dot, tok)
+			}
+
+			return queries, nil
+		}
+
+		if _, err := p.expect(lexer.Dot); err != nil {
+			return nil, err
+		}
+
+		query, err := p.parseSegment()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(queries) > 0 {
+			queries[len(queries)-1].AddChild(query)
+		}
+
+		queries = append(queries, query)
+	}
+}
+
+// ParseValue parses a single GraphQL value literal, such as `[OPEN, CLOSED]`
+func ParseValue(input string) (Value, error) {
+	p := parser{lexer: lexer.New(input)}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(lexer.EOF); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// ReadValue reads a single GraphQL value literal from a lexer, for parsers of
+// larger documents that contain values
+func ReadValue(l *lexer.Lexer) (Value, error) {
+	p := parser{lexer: l}
+	return p.parseValue()
+}
+
+// ReadArguments reads an optional parenthesized argument list from a lexer
+func ReadArguments(l *lexer.Lexer) (map[string]interface{}, error) {
+	p := parser{lexer: l}
+	return p.parseArguments()
+}
+
+type parser struct {
+	lexer *lexer.Lexer
+}
+
+func (p *parser) expect(kind lexer.Kind) (lexer.Token, error) {
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return tok, err
+	}
+
+	if tok.Kind != kind {
+		return tok, lexer.Errorf(tok.Start, "Expected %s, found %s", kind, tok)
+	}
+
+	return tok, nil
+}
+
+func (p *parser) skip(kind lexer.Kind) (bool, error) {
+	tok, err := p.lexer.Peek()
+	if err != nil {
+		return false, err
+	}
+
+	if tok.Kind != kind {
+		return false, nil
+	}
+
+	_, err = p.lexer.Next()
+	return true, err
+}
+
+func (p *parser) parseSegment() (*Query, error) {
+	name, err := p.expect(lexer.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := p.parseArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewQuery(name.Value, args), nil
+}
+
+func (p *parser) parseArguments() (queryArgs, error) {
+	args := queryArgs{}
+
+	if ok, err := p.skip(lexer.ParenL); err != nil || !ok {
+		return args, err
+	}
+
+	for {
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := args[name.Value]; ok {
+			return nil, lexer.Errorf(name.Start, "Duplicate argument %q", name.Value)
+		}
+
+		if _, err := p.expect(lexer.Colon); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		args[name.Value] = value
+
+		if ok, err := p.skip(lexer.ParenR); err != nil || ok {
+			return args, err
+		}
+	}
+}
+
+func (p *parser) parseValue() (Value, error) {
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.Kind {
+	case lexer.Int:
+		i, err := strconv.ParseInt(tok.Value, 10, 64)
+		if err != nil || i > math.MaxInt32 || i < math.MinInt32 {
+			return nil, lexer.Errorf(tok.Start, "Int %s is out of range", tok.Value)
+		}
+
+		return IntValue(i), nil
+	case lexer.Float:
+		f, err := strconv.ParseFloat(tok.Value, 64)
+		if err != nil {
+			return nil, lexer.Errorf(tok.Start, "Float %s is out of range", tok.Value)
+		}
+
+		return FloatValue(f), nil
+	case lexer.String, lexer.BlockString:
+		return StringValue(tok.Value), nil
+	case lexer.Name:
+		switch tok.Value {
+		case "true", "false":
+			return BooleanValue(tok.Value == "true"), nil
+		case "null":
+			return NullValue{}, nil
+		default:
+			return EnumValue(tok.Value), nil
+		}
+	case lexer.Dollar:
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return Variable(name.Value), nil
+	case lexer.BracketL:
+		return p.parseList()
+	case lexer.BraceL:
+		return p.parseObject()
+	}
+
+	return nil, lexer.Errorf(tok.Start, "Unexpected %s", tok)
+}
+
+func (p *parser) parseList() (Value, error) {
+	list := ListValue{}
+
+	for {
+		if ok, err := p.skip(lexer.BracketR); err != nil || ok {
+			return list, err
+		}
+
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, item)
+	}
+}
+
+func (p *parser) parseObject() (Value, error) {
+	obj := ObjectValue{}
+	seen := map[string]bool{}
+
+	for {
+		if ok, err := p.skip(lexer.BraceR); err != nil || ok {
+			return obj, err
+		}
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[name.Value] {
+			return nil, lexer.Errorf(name.Start, "Duplicate input field %q", name.Value)
+		}
+		seen[name.Value] = true
+
+		if _, err := p.expect(lexer.Colon); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		obj = append(obj, ObjectField{Name: name.Value, Value: value})
+	}
+}
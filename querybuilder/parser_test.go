@@ -0,0 +1,98 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	queries, err := ParsePath(".repository(owner: \"jclem\", name: \"graphsh\").owner")
+	assert.NoError(t, err)
+	assert.Len(t, queries, 2)
+	assert.Equal(t, "repository", queries[0].Name)
+	assert.Equal(t, map[string]interface{}{"owner": StringValue("jclem"), "name": StringValue("graphsh")}, queries[0].Args)
+	assert.Equal(t, queries[1], queries[0].Child())
+	assert.Equal(t, `.repository(name: "graphsh", owner: "jclem").owner`, queries[0].Path())
+}
+
+func TestParsePathValues(t *testing.T) {
+	queries, err := ParsePath(`.object(expression: "main:README.md").search(query: "a, b", first: 10, ratio: 1.5, exact: true, body: """
+    multi
+    line
+""")`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"expression": StringValue("main:README.md")}, queries[0].Args)
+	assert.Equal(t, map[string]interface{}{
+		"query": StringValue("a, b"),
+		"first": IntValue(10),
+		"ratio": FloatValue(1.5),
+		"exact": BooleanValue(true),
+		"body":  StringValue("multi\nline"),
+	}, queries[1].Args)
+}
+
+func TestParsePathComplexValues(t *testing.T) {
+	queries, err := ParsePath(".issues(states: [OPEN], orderBy: {field: CREATED_AT, direction: DESC}, after: null, labels: $labels)")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"states":  ListValue{EnumValue("OPEN")},
+		"orderBy": ObjectValue{{"field", EnumValue("CREATED_AT")}, {"direction", EnumValue("DESC")}},
+		"after":   NullValue{},
+		"labels":  Variable("labels"),
+	}, queries[0].Args)
+	assert.Equal(t, ".issues(after: null, labels: $labels, orderBy: {field: CREATED_AT, direction: DESC}, states: [OPEN])", queries[0].Path())
+}
+
+func TestParseValue(t *testing.T) {
+	value, err := ParseValue(`[1, 2.5, "three", [true], {a: {b: null}}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, `[1, 2.5, "three", [true], {a: {b: null}}]`, value.String())
+
+	_, err = ParseValue("[1, 2")
+	assert.EqualError(t, err, "Syntax error at column 6: Unexpected end of input")
+
+	_, err = ParseValue("{a: 1, a: 2}")
+	assert.EqualError(t, err, `Syntax error at column 8: Duplicate input field "a"`)
+
+	_, err = ParseValue("1 2")
+	assert.EqualError(t, err, `Syntax error at column 3: Expected end of input, found int "2"`)
+}
+
+func TestParsePathErrors(t *testing.T) {
+	cases := map[string]string{
+		".":                      "Syntax error at column 2: Expected name, found end of input",
+		".foo..bar":              `Syntax error at column 6: Expected name, found "."`,
+		"foo":                    `Syntax error at column 1: Expected ".", found name "foo"`,
+		".foo(a: 1":              "Syntax error at column 10: Expected name, found end of input",
+		".foo(a 1)":              `Syntax error at column 8: Expected ":", found int "1"`,
+		".foo(a: 1, a: 2)":       `Syntax error at column 12: Duplicate argument "a"`,
+		".foo(a: 99999999999)":   "Syntax error at column 9: Int 99999999999 is out of range",
+		`.foo(a: "unterminated)`: "Syntax error at column 23: Unterminated string",
+		".foo(a: ))":             `Syntax error at column 9: Unexpected ")"`,
+	}
+
+	for input, expected := range cases {
+		_, err := ParsePath(input)
+		assert.EqualError(t, err, expected, input)
+	}
+}
+
+func TestParseSelections(t *testing.T) {
+	selections, err := ParseSelections(`name, me: owner { login } ... on Repository @include(if: $x) { id }`)
+	assert.NoError(t, err)
+	assert.Len(t, selections, 3)
+	assert.Equal(t, "name", selections[0].Name)
+	assert.Equal(t, "me", selections[1].Alias)
+	assert.Equal(t, "owner", selections[1].Name)
+	assert.Equal(t, "login", selections[1].Selections[0].Name)
+	assert.True(t, selections[2].IsFragment())
+	assert.Equal(t, "Repository", selections[2].TypeCondition)
+	assert.Equal(t, []Directive{{Name: "include", Args: map[string]interface{}{"if": Variable("x")}}}, selections[2].Directives)
+
+	_, err = ParseSelections("owner { login")
+	assert.EqualError(t, err, "Syntax error at column 14: Expected name, found end of input")
+
+	_, err = ParseSelections("...Fields")
+	assert.EqualError(t, err, "Syntax error at column 4: Fragment spreads are not supported, use an inline fragment")
+}
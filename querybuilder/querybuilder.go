@@ -14,13 +14,26 @@ type Query struct {
 	child        *Query
 	parent       *Query
 	isRoot       bool
+	variables    []VariableDefinition
+}
+
+// VariableDefinition declares a variable of an operation, such as `$owner: String!`
+type VariableDefinition struct {
+	Name string
+	Type string
 }
 
 type queryArgs = map[string]interface{}
 
 // NewRootQuery creates a new root Query struct
 func NewRootQuery() *Query {
-	return &Query{Name: "query", isRoot: true}
+	return NewRootOperation("query")
+}
+
+// NewRootOperation creates a new root Query struct for an operation type, such
+// as "query" or "mutation"
+func NewRootOperation(operation string) *Query {
+	return &Query{Name: operation, isRoot: true}
 }
 
 // NewQuery creates a new Query struct
@@ -61,16 +74,51 @@ func (q *Query) Path() string {
 			return p.String()
 		}
 
-		p.WriteString(fmt.Sprintf(".%s", q.Name))
-
-		var queryArgs strings.Builder
-		argsToString(q.Args, &queryArgs)
-		p.WriteString(queryArgs.String())
+		p.WriteString(q.segment())
 
 		q = q.child
 	}
 }
 
+// ResponsePath converts a path from a GraphQL response, such as the path of
+// an error, to a human-readable path like those returned by Path
+func (q *Query) ResponsePath(path []interface{}) string {
+	var p strings.Builder
+
+	node := q
+	if q.isRoot {
+		p.WriteString(q.segment())
+		node = q.child
+	}
+
+	for _, elem := range path {
+		switch t := elem.(type) {
+		case string:
+			if node != nil && node.Name == t {
+				p.WriteString(node.segment())
+				node = node.child
+			} else {
+				p.WriteString(fmt.Sprintf(".%s", t))
+				node = nil
+			}
+		case float64:
+			p.WriteString(fmt.Sprintf("[%d]", int(t)))
+		default:
+			p.WriteString(fmt.Sprintf("[%v]", t))
+		}
+	}
+
+	return p.String()
+}
+
+// segment converts a single query node to a path segment
+func (q *Query) segment() string {
+	var queryArgs strings.Builder
+	argsToString(q.Args, &queryArgs)
+
+	return fmt.Sprintf(".%s%s", q.Name, queryArgs.String())
+}
+
 // List converts a query to a list, excluding the root node
 func (q *Query) List() []*Query {
 	var list []*Query
@@ -109,6 +157,12 @@ func (q *Query) ToString(tailQuery string, indent string) string {
 
 	query.WriteString(fmt.Sprintf("%s%s", indent, q.Name))
 
+	if q.isRoot {
+		var queryVariables strings.Builder
+		variablesToString(q.variables, &queryVariables)
+		query.WriteString(queryVariables.String())
+	}
+
 	argsToString(q.Args, &queryArgs)
 
 	query.WriteString(fmt.Sprintf("%s {", queryArgs.String()))
@@ -140,31 +194,38 @@ func (q *Query) ToString(tailQuery string, indent string) string {
 	return query.String()
 }
 
-// WithQuery stringifies the full query with the given query in lowest child
-func (q Query) WithQuery(tailQuery string) string {
+// WithQuery stringifies the full query with the given query in lowest child,
+// declaring the given variables on the root operation
+func (q Query) WithQuery(tailQuery string, variables ...VariableDefinition) string {
+	q.variables = variables
 	return q.ToString(tailQuery, "")
 }
 
-func argsToString(m map[string]interface{}, b *strings.Builder) {
-	eachSortedKey(m, func(k string, v interface{}) {
+func variablesToString(variables []VariableDefinition, b *strings.Builder) {
+	for _, v := range variables {
 		if b.Len() > 0 {
 			b.WriteString(", ")
 		} else {
 			b.WriteRune('(')
 		}
 
-		b.WriteString(fmt.Sprintf("%s: ", k))
+		b.WriteString(fmt.Sprintf("$%s: %s", v.Name, v.Type))
+	}
 
-		switch t := v.(type) {
-		case bool:
-			b.WriteString(fmt.Sprintf("%t", t))
-		case int:
-			b.WriteString(fmt.Sprintf("%d", t))
-		case string:
-			b.WriteString(fmt.Sprintf("%q", t))
-		default:
-			panic(fmt.Sprintf("Unrecognized type in query args %s", t))
+	if b.Len() > 0 {
+		b.WriteRune(')')
+	}
+}
+
+func argsToString(m map[string]interface{}, b *strings.Builder) {
+	eachSortedKey(m, func(k string, v interface{}) {
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		} else {
+			b.WriteRune('(')
 		}
+
+		b.WriteString(fmt.Sprintf("%s: %s", k, ValueOf(v)))
 	})
 
 	if b.Len() > 0 {
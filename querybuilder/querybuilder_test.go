@@ -69,3 +69,64 @@ func TestString(t *testing.T) {
   }
 }`, query.String())
 }
+
+func TestArgsToString(t *testing.T) {
+	query := NewQuery("search", map[string]interface{}{
+		"float":  1.5,
+		"whole":  FloatValue(2),
+		"nil":    nil,
+		"list":   []interface{}{"a", 1},
+		"object": map[string]interface{}{"b": true, "a": EnumValue("ASC")},
+		"escape": "line\nbreak \"quoted\" \x01",
+		"other":  struct{}{},
+	})
+
+	assert.Equal(t, `.search(escape: "line\nbreak \"quoted\" \u0001", float: 1.5, list: ["a", 1], nil: null, object: {a: ASC, b: true}, other: "{}", whole: 2.0)`, query.Path())
+}
+
+func TestWithQuery(t *testing.T) {
+	query := NewRootQuery()
+	query.AddChild(NewQuery("repository", map[string]interface{}{"owner": Variable("owner")}))
+
+	assert.Equal(t, `query($owner: String!) {
+  repository(owner: $owner) {
+name
+  }
+}`, query.WithQuery("name", VariableDefinition{Name: "owner", Type: "String!"}))
+
+	assert.Equal(t, `query {
+  repository(owner: $owner) {
+name
+  }
+}`, query.WithQuery("name"))
+}
+
+func TestToJSON(t *testing.T) {
+	value, err := ParseValue(`{states: [OPEN], first: 1, ratio: 0.5, after: null, name: "x", exact: false}`)
+	assert.NoError(t, err)
+
+	j, err := ToJSON(value)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"states": []interface{}{"OPEN"},
+		"first":  1,
+		"ratio":  0.5,
+		"after":  nil,
+		"name":   "x",
+		"exact":  false,
+	}, j)
+
+	_, err = ToJSON(ListValue{Variable("x")})
+	assert.EqualError(t, err, "Variable $x can not be used as a variable value")
+}
+
+func TestResponsePath(t *testing.T) {
+	root := NewRootQuery()
+	queries, err := ParsePath(`.repository(owner: "jclem", name: "graphsh").issues`)
+	assert.NoError(t, err)
+	root.AddChild(queries[0])
+
+	assert.Equal(t, `.query.repository(name: "graphsh", owner: "jclem").issues.nodes[0].title`, root.ResponsePath([]interface{}{"repository", "issues", "nodes", float64(0), "title"}))
+	assert.Equal(t, ".query.viewer.login", root.ResponsePath([]interface{}{"viewer", "login"}))
+	assert.Equal(t, ".query", root.ResponsePath(nil))
+}
@@ -0,0 +1,164 @@
+package querybuilder
+
+import (
+	"github.com/jclem/graphsh/lexer"
+)
+
+// Selection is a field or inline fragment in a GraphQL selection set
+type Selection struct {
+	// Alias is the field's alias, if any
+	Alias string
+
+	// Name is the field's name, and is empty for inline fragments
+	Name string
+
+	Args       map[string]interface{}
+	Directives []Directive
+
+	// TypeCondition is the type an inline fragment applies to, if any
+	TypeCondition string
+
+	Selections []*Selection
+}
+
+// Directive is a directive applied to a selection, such as `@include(if: $x)`
+type Directive struct {
+	Name string
+	Args map[string]interface{}
+}
+
+// IsFragment is whether the selection is an inline fragment
+func (s Selection) IsFragment() bool {
+	return s.Name == ""
+}
+
+// ParseSelections parses the contents of a selection set, without its
+// surrounding braces, such as `name, owner { login }`
+func ParseSelections(input string) ([]*Selection, error) {
+	p := parser{lexer: lexer.New(input)}
+	return p.parseSelections(lexer.EOF)
+}
+
+func (p *parser) parseSelections(end lexer.Kind) ([]*Selection, error) {
+	var selections []*Selection
+
+	for {
+		if ok, err := p.skip(end); err != nil || ok {
+			return selections, err
+		}
+
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+
+		selections = append(selections, selection)
+	}
+}
+
+func (p *parser) parseSelection() (*Selection, error) {
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.Kind == lexer.Spread {
+		return p.parseInlineFragment()
+	}
+
+	if tok.Kind != lexer.Name {
+		return nil, lexer.Errorf(tok.Start, "Expected %s, found %s", lexer.Name, tok)
+	}
+
+	selection := &Selection{Name: tok.Value}
+
+	if ok, err := p.skip(lexer.Colon); err != nil {
+		return nil, err
+	} else if ok {
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		selection.Alias = selection.Name
+		selection.Name = name.Value
+	}
+
+	if selection.Args, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+
+	if selection.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.skip(lexer.BraceL); err != nil {
+		return nil, err
+	} else if ok {
+		if selection.Selections, err = p.parseSelections(lexer.BraceR); err != nil {
+			return nil, err
+		}
+	}
+
+	return selection, nil
+}
+
+func (p *parser) parseInlineFragment() (*Selection, error) {
+	selection := &Selection{}
+
+	tok, err := p.lexer.Peek()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.Kind == lexer.Name {
+		if tok.Value != "on" {
+			return nil, lexer.Errorf(tok.Start, "Fragment spreads are not supported, use an inline fragment")
+		}
+
+		p.lexer.Next()
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		selection.TypeCondition = name.Value
+	}
+
+	if selection.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(lexer.BraceL); err != nil {
+		return nil, err
+	}
+
+	if selection.Selections, err = p.parseSelections(lexer.BraceR); err != nil {
+		return nil, err
+	}
+
+	return selection, nil
+}
+
+func (p *parser) parseDirectives() ([]Directive, error) {
+	var directives []Directive
+
+	for {
+		if ok, err := p.skip(lexer.At); err != nil || !ok {
+			return directives, err
+		}
+
+		name, err := p.expect(lexer.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, Directive{Name: name.Value, Args: args})
+	}
+}
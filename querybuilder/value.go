@@ -0,0 +1,218 @@
+package querybuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value is a GraphQL input value, such as an argument to a field
+type Value interface {
+	// String serializes the value as a GraphQL literal
+	String() string
+}
+
+type (
+	// IntValue is a GraphQL Int literal
+	IntValue int
+
+	// FloatValue is a GraphQL Float literal
+	FloatValue float64
+
+	// StringValue is a GraphQL String literal
+	StringValue string
+
+	// BooleanValue is a GraphQL Boolean literal
+	BooleanValue bool
+
+	// NullValue is the GraphQL null literal
+	NullValue struct{}
+
+	// EnumValue is a GraphQL enum value, such as `OPEN`
+	EnumValue string
+
+	// ListValue is a GraphQL list literal
+	ListValue []Value
+
+	// ObjectValue is a GraphQL input object literal, with fields kept in the
+	// order they were written
+	ObjectValue []ObjectField
+
+	// ObjectField is a single field of an input object literal
+	ObjectField struct {
+		Name  string
+		Value Value
+	}
+
+	// Variable is a reference to a GraphQL variable, such as `$owner`
+	Variable string
+)
+
+func (v IntValue) String() string {
+	return strconv.Itoa(int(v))
+}
+
+func (v FloatValue) String() string {
+	s := strconv.FormatFloat(float64(v), 'g', -1, 64)
+
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+
+	return s
+}
+
+func (v StringValue) String() string {
+	return quoteString(string(v))
+}
+
+func (v BooleanValue) String() string {
+	return strconv.FormatBool(bool(v))
+}
+
+func (v NullValue) String() string {
+	return "null"
+}
+
+func (v EnumValue) String() string {
+	return string(v)
+}
+
+func (v ListValue) String() string {
+	items := make([]string, 0, len(v))
+
+	for _, item := range v {
+		items = append(items, item.String())
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+}
+
+func (v ObjectValue) String() string {
+	fields := make([]string, 0, len(v))
+
+	for _, field := range v {
+		fields = append(fields, fmt.Sprintf("%s: %s", field.Name, field.Value))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(fields, ", "))
+}
+
+func (v Variable) String() string {
+	return fmt.Sprintf("$%s", string(v))
+}
+
+// ValueOf converts a Go value into a Value
+//
+// Values that are already a Value are returned as-is, and values of
+// unrecognized types are serialized as strings.
+func ValueOf(v interface{}) Value {
+	switch t := v.(type) {
+	case Value:
+		return t
+	case nil:
+		return NullValue{}
+	case bool:
+		return BooleanValue(t)
+	case int:
+		return IntValue(t)
+	case int32:
+		return IntValue(t)
+	case int64:
+		return IntValue(t)
+	case float32:
+		return FloatValue(t)
+	case float64:
+		return FloatValue(t)
+	case string:
+		return StringValue(t)
+	case []interface{}:
+		list := make(ListValue, 0, len(t))
+		for _, item := range t {
+			list = append(list, ValueOf(item))
+		}
+		return list
+	case map[string]interface{}:
+		obj := make(ObjectValue, 0, len(t))
+		eachSortedKey(t, func(k string, v interface{}) {
+			obj = append(obj, ObjectField{Name: k, Value: ValueOf(v)})
+		})
+		return obj
+	default:
+		return StringValue(fmt.Sprint(t))
+	}
+}
+
+var stringEscapes = map[rune]string{
+	'"':  `\"`,
+	'\\': `\\`,
+	'\b': `\b`,
+	'\f': `\f`,
+	'\n': `\n`,
+	'\r': `\r`,
+	'\t': `\t`,
+}
+
+// quoteString serializes a string as a GraphQL string literal
+func quoteString(s string) string {
+	var b strings.Builder
+
+	b.WriteRune('"')
+
+	for _, r := range s {
+		if escaped, ok := stringEscapes[r]; ok {
+			b.WriteString(escaped)
+		} else if r < 0x20 || r == 0x7f {
+			b.WriteString(fmt.Sprintf(`\u%04x`, r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	b.WriteRune('"')
+
+	return b.String()
+}
+
+// ToJSON converts a value into a plain Go value suitable for JSON encoding,
+// such as for sending as a GraphQL variable
+func ToJSON(v Value) (interface{}, error) {
+	switch t := v.(type) {
+	case IntValue:
+		return int(t), nil
+	case FloatValue:
+		return float64(t), nil
+	case StringValue:
+		return string(t), nil
+	case BooleanValue:
+		return bool(t), nil
+	case NullValue:
+		return nil, nil
+	case EnumValue:
+		return string(t), nil
+	case ListValue:
+		list := make([]interface{}, 0, len(t))
+		for _, item := range t {
+			j, err := ToJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, j)
+		}
+		return list, nil
+	case ObjectValue:
+		obj := make(map[string]interface{}, len(t))
+		for _, field := range t {
+			j, err := ToJSON(field.Value)
+			if err != nil {
+				return nil, err
+			}
+			obj[field.Name] = j
+		}
+		return obj, nil
+	case Variable:
+		return nil, fmt.Errorf("Variable %s can not be used as a variable value", t)
+	default:
+		return nil, fmt.Errorf("Unrecognized value %s", v)
+	}
+}
@@ -0,0 +1,419 @@
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jclem/graphsh/introspection"
+)
+
+// Criticality is how a change affects existing clients
+type Criticality string
+
+const (
+	// Breaking changes can make existing queries fail
+	Breaking Criticality = "BREAKING"
+
+	// Dangerous changes can change the results of existing queries
+	Dangerous Criticality = "DANGEROUS"
+
+	// Safe changes do not affect existing queries
+	Safe Criticality = "SAFE"
+)
+
+// ChangeType is the kind of a change
+type ChangeType string
+
+// The kinds of changes between two schemas
+const (
+	TypeAdded       ChangeType = "TYPE_ADDED"
+	TypeRemoved     ChangeType = "TYPE_REMOVED"
+	TypeKindChanged ChangeType = "TYPE_KIND_CHANGED"
+
+	InterfaceAdded   ChangeType = "INTERFACE_ADDED"
+	InterfaceRemoved ChangeType = "INTERFACE_REMOVED"
+
+	UnionMemberAdded   ChangeType = "UNION_MEMBER_ADDED"
+	UnionMemberRemoved ChangeType = "UNION_MEMBER_REMOVED"
+
+	FieldAdded       ChangeType = "FIELD_ADDED"
+	FieldRemoved     ChangeType = "FIELD_REMOVED"
+	FieldTypeChanged ChangeType = "FIELD_TYPE_CHANGED"
+
+	ArgumentAdded          ChangeType = "ARGUMENT_ADDED"
+	ArgumentRemoved        ChangeType = "ARGUMENT_REMOVED"
+	ArgumentTypeChanged    ChangeType = "ARGUMENT_TYPE_CHANGED"
+	ArgumentDefaultChanged ChangeType = "ARGUMENT_DEFAULT_CHANGED"
+
+	InputFieldAdded          ChangeType = "INPUT_FIELD_ADDED"
+	InputFieldRemoved        ChangeType = "INPUT_FIELD_REMOVED"
+	InputFieldTypeChanged    ChangeType = "INPUT_FIELD_TYPE_CHANGED"
+	InputFieldDefaultChanged ChangeType = "INPUT_FIELD_DEFAULT_CHANGED"
+
+	EnumValueAdded   ChangeType = "ENUM_VALUE_ADDED"
+	EnumValueRemoved ChangeType = "ENUM_VALUE_REMOVED"
+
+	Deprecated               ChangeType = "DEPRECATED"
+	Undeprecated             ChangeType = "UNDEPRECATED"
+	DeprecationReasonChanged ChangeType = "DEPRECATION_REASON_CHANGED"
+)
+
+// Change is a difference between two schemas
+type Change struct {
+	Type        ChangeType  `json:"type"`
+	Criticality Criticality `json:"criticality"`
+
+	// Path is the schema coordinate of the changed element, such as
+	// `Query.repository(owner:)`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Diff finds the changes from an old schema to a new one, with breaking
+// changes first, then dangerous changes, then safe changes
+func Diff(old *introspection.Schema, new *introspection.Schema) []Change {
+	d := &differ{}
+
+	for _, name := range union(typeNames(old), typeNames(new)) {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+
+		oldType, inOld := old.LookupType(name)
+		newType, inNew := new.LookupType(name)
+
+		switch {
+		case !inNew:
+			d.add(TypeRemoved, Breaking, name, "Type %q was removed", name)
+		case !inOld:
+			d.add(TypeAdded, Safe, name, "Type %q was added", name)
+		case oldType.Kind != newType.Kind:
+			d.add(TypeKindChanged, Breaking, name, "Type %q changed from %s to %s", name, kindName(oldType.Kind), kindName(newType.Kind))
+		default:
+			d.typ(oldType, newType)
+		}
+	}
+
+	ranks := map[Criticality]int{Breaking: 0, Dangerous: 1, Safe: 2}
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return ranks[d.changes[i].Criticality] < ranks[d.changes[j].Criticality]
+	})
+
+	return d.changes
+}
+
+// differ collects changes as it compares two schemas
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(typ ChangeType, criticality Criticality, path string, format string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Type:        typ,
+		Criticality: criticality,
+		Path:        path,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// typ compares two versions of a type of the same kind
+func (d *differ) typ(old *introspection.FullType, new *introspection.FullType) {
+	switch old.Kind {
+	case "OBJECT", "INTERFACE":
+		d.refs(old.Name, old.Interfaces, new.Interfaces, InterfaceAdded, InterfaceRemoved, "Interface %q")
+		d.fields(old, new)
+	case "UNION":
+		d.refs(old.Name, old.PossibleTypes, new.PossibleTypes, UnionMemberAdded, UnionMemberRemoved, "Member %q")
+	case "ENUM":
+		d.enumValues(old, new)
+	case "INPUT_OBJECT":
+		d.inputValues(old.Name, "Input field %q of type %q", old.Name+".%s", old.InputFields, new.InputFields, inputFieldChanges)
+	}
+}
+
+// refs compares the interfaces of an object or interface type, or the
+// members of a union type, where additions are dangerous because they can
+// match fragments that did not match before
+func (d *differ) refs(typeName string, old []introspection.TypeRef, new []introspection.TypeRef, added ChangeType, removed ChangeType, subject string) {
+	oldNames := refNames(old)
+	newNames := refNames(new)
+
+	for _, name := range union(oldNames, newNames) {
+		switch {
+		case !contains(newNames, name):
+			d.add(removed, Breaking, typeName, subject+" was removed from type %q", name, typeName)
+		case !contains(oldNames, name):
+			d.add(added, Dangerous, typeName, subject+" was added to type %q", name, typeName)
+		}
+	}
+}
+
+func (d *differ) fields(old *introspection.FullType, new *introspection.FullType) {
+	for _, name := range union(fieldNames(old), fieldNames(new)) {
+		path := old.Name + "." + name
+		oldField, inOld := old.GetField(name)
+		newField, inNew := new.GetField(name)
+
+		switch {
+		case !inNew:
+			d.add(FieldRemoved, Breaking, path, "Field %q was removed", path)
+			continue
+		case !inOld:
+			d.add(FieldAdded, Safe, path, "Field %q was added", path)
+			continue
+		}
+
+		if oldType, newType := oldField.Type.String(), newField.Type.String(); oldType != newType {
+			criticality := Breaking
+			if isSafeOutputChange(&oldField.Type, &newField.Type) {
+				criticality = Safe
+			}
+
+			d.add(FieldTypeChanged, criticality, path, "Field %q changed type from %s to %s", path, oldType, newType)
+		}
+
+		d.deprecation(path, fmt.Sprintf("Field %q", path), oldField.IsDeprecated, oldField.DeprecationReason, newField.IsDeprecated, newField.DeprecationReason)
+		d.inputValues(path, "Argument %q of field %q", path+"(%s:)", oldField.Args, newField.Args, argumentChanges)
+	}
+}
+
+// inputValueChanges are the kinds of changes to arguments or input fields
+type inputValueChanges struct {
+	added, removed, typeChanged, defaultChanged ChangeType
+}
+
+var argumentChanges = inputValueChanges{ArgumentAdded, ArgumentRemoved, ArgumentTypeChanged, ArgumentDefaultChanged}
+
+var inputFieldChanges = inputValueChanges{InputFieldAdded, InputFieldRemoved, InputFieldTypeChanged, InputFieldDefaultChanged}
+
+// inputValues compares the arguments of a field or the fields of an input
+// type, where subject and pathFormat describe an input value by name
+func (d *differ) inputValues(owner string, subject string, pathFormat string, old []introspection.InputValue, new []introspection.InputValue, changes inputValueChanges) {
+	for _, name := range union(inputValueNames(old), inputValueNames(new)) {
+		path := fmt.Sprintf(pathFormat, name)
+		description := fmt.Sprintf(subject, name, owner)
+		oldValue, inOld := findInputValue(old, name)
+		newValue, inNew := findInputValue(new, name)
+
+		switch {
+		case !inNew:
+			d.add(changes.removed, Breaking, path, "%s was removed", description)
+			continue
+		case !inOld && newValue.IsRequired():
+			d.add(changes.added, Breaking, path, "Required %s was added", lowerFirst(description))
+			continue
+		case !inOld:
+			// Clients passing no value may get different results than before
+			d.add(changes.added, Dangerous, path, "Optional %s was added", lowerFirst(description))
+			continue
+		}
+
+		if oldType, newType := oldValue.Type.String(), newValue.Type.String(); oldType != newType {
+			criticality := Breaking
+			if isSafeInputChange(&oldValue.Type, &newValue.Type) {
+				criticality = Safe
+			}
+
+			d.add(changes.typeChanged, criticality, path, "%s changed type from %s to %s", description, oldType, newType)
+		}
+
+		if oldValue.DefaultValue != newValue.DefaultValue {
+			d.add(changes.defaultChanged, Dangerous, path, "%s changed default value from %s to %s", description, valueOrNone(oldValue.DefaultValue), valueOrNone(newValue.DefaultValue))
+		}
+
+		d.deprecation(path, description, oldValue.IsDeprecated, oldValue.DeprecationReason, newValue.IsDeprecated, newValue.DeprecationReason)
+	}
+}
+
+func (d *differ) enumValues(old *introspection.FullType, new *introspection.FullType) {
+	oldValues := map[string]introspection.EnumValue{}
+	for _, value := range old.EnumValues {
+		oldValues[value.Name] = value
+	}
+
+	newValues := map[string]introspection.EnumValue{}
+	for _, value := range new.EnumValues {
+		newValues[value.Name] = value
+	}
+
+	for _, name := range union(keys(oldValues), keys(newValues)) {
+		path := old.Name + "." + name
+		oldValue, inOld := oldValues[name]
+		newValue, inNew := newValues[name]
+
+		switch {
+		case !inNew:
+			d.add(EnumValueRemoved, Breaking, path, "Enum value %q was removed", path)
+		case !inOld:
+			// Clients may not handle a value they have never seen
+			d.add(EnumValueAdded, Dangerous, path, "Enum value %q was added", path)
+		default:
+			d.deprecation(path, fmt.Sprintf("Enum value %q", path), oldValue.IsDeprecated, oldValue.DeprecationReason, newValue.IsDeprecated, newValue.DeprecationReason)
+		}
+	}
+}
+
+func (d *differ) deprecation(path string, description string, wasDeprecated bool, oldReason string, isDeprecated bool, newReason string) {
+	switch {
+	case !wasDeprecated && isDeprecated:
+		d.add(Deprecated, Safe, path, "%s was deprecated%s", description, because(newReason))
+	case wasDeprecated && !isDeprecated:
+		d.add(Undeprecated, Safe, path, "%s is no longer deprecated", description)
+	case wasDeprecated && oldReason != newReason:
+		d.add(DeprecationReasonChanged, Safe, path, "%s changed its deprecation reason%s", description, because(newReason))
+	}
+}
+
+// isSafeOutputChange is whether clients reading a field can handle its type
+// changing from old to new, which is when the new type is the same or
+// stricter
+func isSafeOutputChange(old *introspection.TypeRef, new *introspection.TypeRef) bool {
+	switch {
+	case old.Kind == "NON_NULL":
+		return new.Kind == "NON_NULL" && isSafeOutputChange(ofType(old), ofType(new))
+	case new.Kind == "NON_NULL":
+		return isSafeOutputChange(old, ofType(new))
+	case old.Kind == "LIST":
+		return new.Kind == "LIST" && isSafeOutputChange(ofType(old), ofType(new))
+	}
+
+	return new.Kind != "LIST" && old.Name == new.Name
+}
+
+// isSafeInputChange is whether clients passing an argument or input field
+// can still pass it when its type changes from old to new, which is when the
+// new type is the same or looser
+func isSafeInputChange(old *introspection.TypeRef, new *introspection.TypeRef) bool {
+	switch {
+	case old.Kind == "NON_NULL" && new.Kind == "NON_NULL":
+		return isSafeInputChange(ofType(old), ofType(new))
+	case old.Kind == "NON_NULL":
+		return isSafeInputChange(ofType(old), new)
+	case new.Kind == "NON_NULL":
+		return false
+	case old.Kind == "LIST":
+		return new.Kind == "LIST" && isSafeInputChange(ofType(old), ofType(new))
+	}
+
+	return new.Kind != "LIST" && old.Name == new.Name
+}
+
+// ofType gets the type wrapped by a list or non-null type, which may be
+// missing from a truncated introspection result
+func ofType(ref *introspection.TypeRef) *introspection.TypeRef {
+	if ref.OfType == nil {
+		return &introspection.TypeRef{}
+	}
+
+	return ref.OfType
+}
+
+func typeNames(schema *introspection.Schema) []string {
+	names := make([]string, 0, len(schema.Types))
+	for _, typ := range schema.Types {
+		names = append(names, typ.Name)
+	}
+
+	return names
+}
+
+func fieldNames(typ *introspection.FullType) []string {
+	names := make([]string, 0, len(typ.Fields))
+	for _, field := range typ.Fields {
+		names = append(names, field.Name)
+	}
+
+	return names
+}
+
+func inputValueNames(values []introspection.InputValue) []string {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		names = append(names, value.Name)
+	}
+
+	return names
+}
+
+func refNames(refs []introspection.TypeRef) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+
+	return names
+}
+
+func keys(values map[string]introspection.EnumValue) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func findInputValue(values []introspection.InputValue, name string) (*introspection.InputValue, bool) {
+	for i := range values {
+		if values[i].Name == name {
+			return &values[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// union gets the sorted names that are in either list
+func union(a []string, b []string) []string {
+	seen := map[string]bool{}
+	var names []string
+
+	for _, name := range append(append([]string{}, a...), b...) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func kindName(kind string) string {
+	return strings.Replace(strings.ToLower(kind), "_", " ", -1)
+}
+
+func because(reason string) string {
+	if reason == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(": %s", reason)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+
+	return value
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,146 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/jclem/graphsh/introspection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseSDL(t *testing.T, sdl string) *introspection.Schema {
+	schema, err := introspection.ParseSDL(sdl)
+	require.NoError(t, err)
+
+	return schema
+}
+
+const oldSchema = `
+type Query {
+  repository(owner: String!, name: String!): Repository
+  search(query: String!, first: Int = 10): [Result!]
+  viewer: User
+  legacy: String
+}
+
+interface Node { id: ID! }
+
+type Repository implements Node {
+  id: ID!
+  name: String
+  issues(states: [IssueState!]): [Issue]
+  stars: Int!
+}
+
+type Issue { title: String, state: IssueState @deprecated(reason: "Use status") }
+
+type User { login: String! }
+
+union Result = Repository | Issue
+
+enum IssueState { OPEN, CLOSED, LOCKED }
+
+input IssueFilter { state: IssueState, assignee: String! }
+
+scalar Legacy
+`
+
+const newSchema = `
+type Query {
+  repository(owner: String!, name: String!, followRenames: Boolean): Repository
+  search(query: String, first: Int = 20, type: String!): [Result!]!
+  viewer: User @deprecated(reason: "Use me")
+  me: User
+}
+
+interface Node { id: ID! }
+
+type Repository implements Node {
+  id: ID!
+  name: String!
+  issues(states: IssueState): [Issue]
+  stars: Int
+}
+
+type Issue implements Node { id: ID!, title: String, state: IssueState }
+
+type User { login: String! }
+
+union Result = Repository | Issue | User
+
+enum IssueState { OPEN, CLOSED, DRAFT }
+
+input IssueFilter { state: IssueState, labels: [String!] }
+
+interface Legacy { id: ID! }
+`
+
+func TestDiff(t *testing.T) {
+	changes := Diff(mustParseSDL(t, oldSchema), mustParseSDL(t, newSchema))
+
+	var messages []string
+	for _, change := range changes {
+		messages = append(messages, string(change.Criticality)+" "+change.Message)
+	}
+
+	assert.Equal(t, []string{
+		`BREAKING Input field "assignee" of type "IssueFilter" was removed`,
+		`BREAKING Enum value "IssueState.LOCKED" was removed`,
+		`BREAKING Type "Legacy" changed from scalar to interface`,
+		`BREAKING Field "Query.legacy" was removed`,
+		`BREAKING Required argument "type" of field "Query.search" was added`,
+		`BREAKING Argument "states" of field "Repository.issues" changed type from [IssueState!] to IssueState`,
+		`BREAKING Field "Repository.stars" changed type from Int! to Int`,
+		`DANGEROUS Interface "Node" was added to type "Issue"`,
+		`DANGEROUS Optional input field "labels" of type "IssueFilter" was added`,
+		`DANGEROUS Enum value "IssueState.DRAFT" was added`,
+		`DANGEROUS Optional argument "followRenames" of field "Query.repository" was added`,
+		`DANGEROUS Argument "first" of field "Query.search" changed default value from 10 to 20`,
+		`DANGEROUS Member "User" was added to type "Result"`,
+		`SAFE Field "Issue.id" was added`,
+		`SAFE Field "Issue.state" is no longer deprecated`,
+		`SAFE Field "Query.me" was added`,
+		`SAFE Field "Query.search" changed type from [Result!] to [Result!]!`,
+		`SAFE Argument "query" of field "Query.search" changed type from String! to String`,
+		`SAFE Field "Query.viewer" was deprecated: Use me`,
+		`SAFE Field "Repository.name" changed type from String to String!`,
+	}, messages)
+
+	assert.Equal(t, Change{
+		Type:        ArgumentAdded,
+		Criticality: Breaking,
+		Path:        "Query.search(type:)",
+		Message:     `Required argument "type" of field "Query.search" was added`,
+	}, changes[4])
+}
+
+func TestDiffIdentical(t *testing.T) {
+	assert.Empty(t, Diff(mustParseSDL(t, oldSchema), mustParseSDL(t, oldSchema)))
+}
+
+func TestIsSafeChange(t *testing.T) {
+	ref := func(sdl string) *introspection.TypeRef {
+		schema := mustParseSDL(t, "type Query { f: "+sdl+" }")
+		query, _ := schema.GetQueryType()
+		field, _ := query.GetField("f")
+		return &field.Type
+	}
+
+	tests := []struct {
+		old, new      string
+		output, input bool
+	}{
+		{"Int", "Int", true, true},
+		{"Int", "Int!", true, false},
+		{"Int!", "Int", false, true},
+		{"[Int]", "[Int!]!", true, false},
+		{"[Int!]!", "[Int]", false, true},
+		{"Int", "[Int]", false, false},
+		{"Int", "String", false, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.output, isSafeOutputChange(ref(test.old), ref(test.new)), "%s to %s as output", test.old, test.new)
+		assert.Equal(t, test.input, isSafeInputChange(ref(test.old), ref(test.new)), "%s to %s as input", test.old, test.new)
+	}
+}
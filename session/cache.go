@@ -0,0 +1,71 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultSchemaTTL is how long a cached schema is used before it is
+// introspected again
+const DefaultSchemaTTL = 24 * time.Hour
+
+var authHeaderNames = []string{"authorization", "cookie", "token", "key", "secret", "session"}
+
+// schemaCachePath gets the path of the schema cache file for an endpoint,
+// under the XDG cache directory
+//
+// The schema may differ between users, so the file name includes a hash of
+// the headers that look like they authenticate the user.
+func schemaCachePath(endpoint string, headers map[string][]string) (string, error) {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		cacheHome = filepath.Join(home, ".cache")
+	}
+
+	name := fmt.Sprintf("%s-%s.json", endpointFileName(endpoint), hashAuthHeaders(headers))
+
+	return filepath.Join(cacheHome, "graphsh", "schemas", name), nil
+}
+
+// hashAuthHeaders hashes the headers that look like they authenticate the user
+func hashAuthHeaders(headers map[string][]string) string {
+	var names []string
+	for name := range headers {
+		if isAuthHeader(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		for _, value := range headers[name] {
+			fmt.Fprintf(hash, "%s: %s\n", strings.ToLower(name), value)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+func isAuthHeader(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, authName := range authHeaderNames {
+		if strings.Contains(name, authName) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaCachePath(t *testing.T) {
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+
+	headers := map[string][]string{"Authorization": {"Bearer a"}, "Accept": {"application/json"}}
+
+	path, err := schemaCachePath("https://api.github.com/graphql", headers)
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/cache/graphsh/schemas/"+endpointFileName("https://api.github.com/graphql")+"-"+hashAuthHeaders(headers)+".json", path)
+
+	// Endpoints that only differ in unsafe characters have their own files
+	other, err := schemaCachePath("https://api_github.com/graphql", headers)
+	require.NoError(t, err)
+	assert.NotEqual(t, path, other)
+
+	// Only headers that look like they authenticate the user affect the hash
+	assert.Equal(t, hashAuthHeaders(headers), hashAuthHeaders(map[string][]string{"Authorization": {"Bearer a"}}))
+	assert.NotEqual(t, hashAuthHeaders(headers), hashAuthHeaders(map[string][]string{"Authorization": {"Bearer b"}}))
+	assert.NotEqual(t, hashAuthHeaders(nil), hashAuthHeaders(map[string][]string{"X-Api-Key": {"a"}}))
+	assert.Len(t, hashAuthHeaders(nil), 16)
+}
@@ -0,0 +1,235 @@
+package session
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jclem/graphsh/command"
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/lexer"
+)
+
+// completer implements readline.AutoCompleter using the session's schema
+type completer struct {
+	session *Session
+}
+
+// Do implements readline.AutoCompleter
+func (c completer) Do(line []rune, pos int) ([][]rune, int) {
+	prefix, candidates := c.candidates(string(line[:pos]))
+
+	var suffixes [][]rune
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && candidate != prefix {
+			suffixes = append(suffixes, []rune(candidate[len(prefix):]))
+		}
+	}
+
+	return suffixes, len([]rune(prefix))
+}
+
+// candidates returns the partial word being completed at the end of the
+// input, and every candidate that may replace it
+func (c completer) candidates(input string) (string, []string) {
+	switch {
+	case strings.HasPrefix(input, "help "):
+		return strings.TrimPrefix(input, "help "), command.Names()
+	case strings.HasPrefix(input, "on "):
+		types, err := c.session.schema.GetPossibleTypes(c.session.RootQuery())
+		if err != nil {
+			return "", nil
+		}
+
+		return strings.TrimPrefix(input, "on "), types
+	case strings.HasPrefix(input, "args "):
+		typ, err := c.session.schema.GetType(c.session.RootQuery())
+		if err != nil {
+			return "", nil
+		}
+
+		var names []string
+		for _, field := range typ.Fields {
+			if len(field.Args) > 0 {
+				names = append(names, field.Name)
+			}
+		}
+
+		return strings.TrimPrefix(input, "args "), names
+	case strings.HasPrefix(input, "."):
+		return c.pathCandidates(input)
+	}
+
+	return "", nil
+}
+
+// completionFrame is an open argument list, input object, or list in a path
+type completionFrame struct {
+	// inputs are the arguments or input fields that may be named in this frame
+	inputs []introspection.InputValue
+
+	// isList is whether this frame is a list, whose items are of itemType
+	isList   bool
+	itemType *introspection.TypeRef
+
+	// expecting is the type of the value expected next, after an input name
+	expecting *introspection.TypeRef
+	isValue   bool
+}
+
+func (c completer) pathCandidates(input string) (string, []string) {
+	tokens, err := lexer.Tokenize(input)
+	if err != nil || len(tokens) == 0 {
+		return "", nil
+	}
+
+	prefix := ""
+	if last := tokens[len(tokens)-1]; last.Kind == lexer.Name && last.End-1 == len([]rune(input)) {
+		prefix = last.Value
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	typ, err := c.session.schema.GetType(c.session.RootQuery())
+	if err != nil {
+		return "", nil
+	}
+
+	var field *introspection.Field
+	var stack []*completionFrame
+
+	for i, tok := range tokens {
+		var frame *completionFrame
+		if len(stack) > 0 {
+			frame = stack[len(stack)-1]
+		}
+
+		switch {
+		case frame == nil && tok.Kind == lexer.Name && i > 0 && tokens[i-1].Kind == lexer.Dot:
+			field = nil
+			if typ != nil {
+				if f, ok := typ.GetField(tok.Value); ok {
+					field = f
+				}
+			}
+
+			typ = nil
+			if field != nil {
+				typ, _ = c.session.schema.LookupType(field.GetTypeName())
+			}
+		case frame == nil && tok.Kind == lexer.ParenL:
+			frame = &completionFrame{}
+			if field != nil {
+				frame.inputs = field.Args
+			}
+			stack = append(stack, frame)
+		case frame == nil:
+		case tok.Kind == lexer.ParenR || tok.Kind == lexer.BraceR || tok.Kind == lexer.BracketR:
+			stack = stack[:len(stack)-1]
+		case tok.Kind == lexer.Colon:
+			frame.isValue = true
+		case tok.Kind == lexer.Name && !frame.isList && !frame.isValue:
+			frame.expecting = nil
+			for j := range frame.inputs {
+				if frame.inputs[j].Name == tok.Value {
+					frame.expecting = &frame.inputs[j].Type
+					break
+				}
+			}
+		case tok.Kind == lexer.BracketL || tok.Kind == lexer.BraceL:
+			expecting := frame.valueType()
+			frame.isValue = false
+
+			child := &completionFrame{isList: tok.Kind == lexer.BracketL}
+			if expecting != nil && child.isList && expecting.Nullable().Kind == "LIST" {
+				child.itemType = expecting.Nullable().OfType
+			} else if expecting != nil && !child.isList {
+				if inputType, ok := c.session.schema.LookupType(expecting.NamedType()); ok {
+					child.inputs = inputType.InputFields
+				}
+			}
+			stack = append(stack, child)
+		case tok.Kind == lexer.Dollar:
+		default:
+			frame.isValue = false
+		}
+	}
+
+	if len(tokens) > 0 && tokens[len(tokens)-1].Kind == lexer.Dollar {
+		return prefix, c.variableCandidates()
+	}
+
+	if len(stack) == 0 {
+		if len(tokens) == 0 || tokens[len(tokens)-1].Kind != lexer.Dot || typ == nil {
+			return "", nil
+		}
+
+		names := make([]string, 0, len(typ.Fields))
+		for _, f := range typ.Fields {
+			names = append(names, f.Name)
+		}
+
+		return prefix, names
+	}
+
+	frame := stack[len(stack)-1]
+	if frame.isList || frame.isValue {
+		return prefix, c.valueCandidates(frame.valueType())
+	}
+
+	names := make([]string, 0, len(frame.inputs))
+	for _, input := range frame.inputs {
+		names = append(names, input.Name+": ")
+	}
+
+	return prefix, names
+}
+
+// valueType is the type of value expected next in the frame
+func (f completionFrame) valueType() *introspection.TypeRef {
+	if f.isList {
+		return f.itemType
+	}
+
+	if f.isValue {
+		return f.expecting
+	}
+
+	return nil
+}
+
+func (c completer) variableCandidates() []string {
+	names := make([]string, 0, len(c.session.variables))
+	for name := range c.session.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (c completer) valueCandidates(ref *introspection.TypeRef) []string {
+	if ref == nil {
+		return nil
+	}
+
+	if ref.Nullable().Kind == "LIST" {
+		return c.valueCandidates(ref.Nullable().OfType)
+	}
+
+	typ, ok := c.session.schema.LookupType(ref.NamedType())
+	if !ok {
+		return nil
+	}
+
+	switch {
+	case typ.Kind == "ENUM":
+		names := make([]string, 0, len(typ.EnumValues))
+		for _, value := range typ.EnumValues {
+			names = append(names, value.Name)
+		}
+		return names
+	case typ.Name == "Boolean":
+		return []string{"true", "false"}
+	}
+
+	return nil
+}
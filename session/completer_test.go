@@ -0,0 +1,98 @@
+package session
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fileQuerier string
+
+func (f fileQuerier) Query(query string, variables map[string]interface{}) (*graphql.Response, error) {
+	body, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return nil, err
+	}
+
+	return graphql.ParseResponse(body)
+}
+
+func newTestSession(t *testing.T) *Session {
+	client := fileQuerier("../introspection/testdata/schema.json")
+	schema, err := introspection.LoadSchema(client)
+	require.NoError(t, err)
+
+	query := querybuilder.NewRootQuery()
+
+	return &Session{
+		client:       client,
+		rootQuery:    query,
+		currentQuery: query,
+		schema:       schema,
+		variables:    map[string]querybuilder.Value{"owner": querybuilder.StringValue("jclem")},
+	}
+}
+
+func complete(c completer, line string) []string {
+	suffixes, length := c.Do([]rune(line), len([]rune(line)))
+
+	var candidates []string
+	for _, suffix := range suffixes {
+		candidates = append(candidates, line[len(line)-length:]+string(suffix))
+	}
+
+	return candidates
+}
+
+func TestCompleteFields(t *testing.T) {
+	c := completer{newTestSession(t)}
+
+	assert.Equal(t, []string{"repository", "repositoryOwner"}, complete(c, ".rep"))
+	assert.Equal(t, []string{"owner"}, complete(c, `.repository(owner: "a, b", name: "x.y").ow`))
+	assert.Equal(t, []string{"login", "repositories"}, complete(c, `.repository(owner: "a", name: "b").owner.`))
+	assert.Empty(t, complete(c, ".nope."))
+}
+
+func TestCompleteArguments(t *testing.T) {
+	c := completer{newTestSession(t)}
+
+	assert.Equal(t, []string{"owner: "}, complete(c, ".repository(o"))
+	assert.Equal(t, []string{"name: "}, complete(c, `.repository(owner: "jclem", n`))
+	assert.Equal(t, []string{"OPEN"}, complete(c, `.repository(owner: "a", name: "b").issues(states: [CLOSED, O`))
+	assert.Equal(t, []string{"CREATED_AT", "UPDATED_AT"}, complete(c, `.repository(owner: "a", name: "b").issues(orderBy: {field: `))
+	assert.Equal(t, []string{"direction: "}, complete(c, `.repository(owner: "a", name: "b").issues(orderBy: {field: CREATED_AT, d`))
+	assert.Equal(t, []string{"owner"}, complete(c, ".repository(owner: $o"))
+}
+
+func TestCompleteOn(t *testing.T) {
+	s := newTestSession(t)
+	c := completer{s}
+
+	queries, err := querybuilder.ParsePath(`.repository(owner: "a", name: "b").owner`)
+	require.NoError(t, err)
+	s.rootQuery.AddChild(queries[0])
+	s.currentQuery = queries[1]
+	s.currentQuery.ConcreteType = "User"
+
+	assert.Equal(t, []string{"Organization", "User"}, complete(c, "on "))
+	assert.Equal(t, []string{"bio"}, complete(c, ".b"))
+}
+
+func TestCompleteArgs(t *testing.T) {
+	s := newTestSession(t)
+	c := completer{s}
+
+	assert.Equal(t, []string{"repository", "repositoryOwner", "node", "search"}, complete(c, "args "))
+	assert.Equal(t, []string{"repository", "repositoryOwner"}, complete(c, "args rep"))
+}
+
+func TestCompleteHelp(t *testing.T) {
+	c := completer{newTestSession(t)}
+
+	assert.Equal(t, []string{"on"}, complete(c, "help o"))
+}
@@ -0,0 +1,134 @@
+package session
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// historyLimit is the maximum number of history entries kept, which matches
+// readline's default
+const historyLimit = 500
+
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// historyPath gets the path of the history file for an endpoint, under the
+// XDG data directory
+func historyPath(endpoint string) (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	dir := filepath.Join(dataHome, "graphsh", "history")
+
+	// The history may contain secrets, so keep it private
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, endpointFileName(endpoint)), nil
+}
+
+// endpointFileName converts an endpoint into a file name, which ends with a
+// hash of the endpoint, since replacing unsafe characters can give different
+// endpoints the same name
+func endpointFileName(endpoint string) string {
+	name := strings.Trim(unsafePathChars.ReplaceAllString(endpoint, "_"), "_")
+	hash := sha256.Sum256([]byte(endpoint))
+
+	return fmt.Sprintf("%s-%s", name, hex.EncodeToString(hash[:])[:8])
+}
+
+// loadHistory reads the entries in a history file
+func loadHistory(path string) ([]string, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var history []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			history = append(history, line)
+		}
+	}
+
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+
+	return history, scanner.Err()
+}
+
+// appendHistory adds an entry to a history, dropping the oldest entries past
+// historyLimit so that it numbers entries the same way as the history file
+func appendHistory(history []string, line string) []string {
+	history = append(history, line)
+
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+
+	return history
+}
+
+var secretPattern = regexp.MustCompile(`(?i)(\b(authorization|proxy-authorization|cookie|set-cookie|x-[a-z-]*(token|key|secret|auth))\s*:|\bbearer\s+\S|\b(token|secret|password|api[-_]?key)\s*[:=]\s*\S)`)
+
+// isSecret is whether a line looks like it contains a header-like secret
+func isSecret(line string) bool {
+	return secretPattern.MatchString(line)
+}
+
+// expandHistory replaces a history reference such as `!!`, `!42`, `!-2` or
+// `!prefix` with the history entry it refers to
+func expandHistory(line string, history []string) (string, error) {
+	if !strings.HasPrefix(line, "!") || line == "!" {
+		return line, nil
+	}
+
+	ref := line[1:]
+
+	if ref == "!" {
+		if len(history) == 0 {
+			return "", fmt.Errorf("%s: event not found", line)
+		}
+
+		return history[len(history)-1], nil
+	}
+
+	if n, err := strconv.Atoi(ref); err == nil {
+		index := n - 1
+		if n < 0 {
+			index = len(history) + n
+		}
+
+		if index < 0 || index >= len(history) {
+			return "", fmt.Errorf("%s: event not found", line)
+		}
+
+		return history[index], nil
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		if strings.HasPrefix(history[i], ref) {
+			return history[i], nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: event not found", line)
+}
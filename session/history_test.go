@@ -0,0 +1,101 @@
+package session
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandHistory(t *testing.T) {
+	history := []string{"ls", ".viewer", "pp"}
+
+	cases := map[string]string{
+		"ls":  "ls",
+		"!":   "!",
+		"!!":  "pp",
+		"!1":  "ls",
+		"!-2": ".viewer",
+		"!.v": ".viewer",
+	}
+
+	for input, expected := range cases {
+		expanded, err := expandHistory(input, history)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, expanded, input)
+	}
+
+	for _, input := range []string{"!4", "!0", "!-4", "!nope"} {
+		_, err := expandHistory(input, history)
+		assert.EqualError(t, err, input+": event not found")
+	}
+
+	_, err := expandHistory("!!", nil)
+	assert.EqualError(t, err, "!!: event not found")
+}
+
+func TestIsSecret(t *testing.T) {
+	assert.True(t, isSecret(`set $auth "Authorization: Bearer abc"`))
+	assert.True(t, isSecret(`set $header "X-Api-Token: abc"`))
+	assert.True(t, isSecret(`{login(password: "hunter2")}`))
+	assert.False(t, isSecret(".viewer.tokens"))
+	assert.False(t, isSecret(`.repository(owner: "jclem", name: "graphsh")`))
+}
+
+func TestHistoryPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphsh")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	os.Setenv("XDG_DATA_HOME", dir)
+	defer os.Unsetenv("XDG_DATA_HOME")
+
+	path, err := historyPath("https://api.github.com/graphql")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "graphsh", "history", endpointFileName("https://api.github.com/graphql")), path)
+	assert.Regexp(t, `/https_api\.github\.com_graphql-[0-9a-f]{8}$`, path)
+
+	// Endpoints that only differ in unsafe characters have their own files
+	assert.NotEqual(t, endpointFileName("https://a/b_c"), endpointFileName("https://a_b/c"))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("ls\n\n.viewer\n"), 0600))
+	history, err := loadHistory(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ls", ".viewer"}, history)
+}
+
+func TestAppendHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphsh")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var history []string
+	var lines []string
+
+	for i := 1; i <= historyLimit+10; i++ {
+		line := fmt.Sprintf("set $a %d", i)
+		history = appendHistory(history, line)
+		lines = append(lines, line)
+	}
+
+	assert.Len(t, history, historyLimit)
+	assert.Equal(t, "set $a 11", history[0])
+	assert.Equal(t, fmt.Sprintf("set $a %d", historyLimit+10), history[historyLimit-1])
+
+	// Entries are numbered the same as after loading the history file again
+	path := filepath.Join(dir, "history")
+	require.NoError(t, ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600))
+
+	loaded, err := loadHistory(path)
+	require.NoError(t, err)
+	assert.Equal(t, loaded, history)
+
+	expanded, err := expandHistory("!1", history)
+	assert.NoError(t, err)
+	assert.Equal(t, "set $a 11", expanded)
+}
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"net/url"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// DefaultPrompt is the prompt template used when none is given
+const DefaultPrompt = "› "
+
+// promptData is the data available to prompt templates
+type promptData struct {
+	// Path is the current query path, such as `.query.viewer`
+	Path string
+
+	// Type is the concrete type applied to the current query node, if any
+	Type string
+
+	// Host is the host of the session's endpoint
+	Host string
+
+	// Operation is the root operation type, such as "query"
+	Operation string
+}
+
+var promptFuncs = template.FuncMap{
+	"truncate": truncateLeft,
+}
+
+// parsePrompt parses a prompt template
+func parsePrompt(text string) (*template.Template, error) {
+	return template.New("prompt").Funcs(promptFuncs).Parse(text)
+}
+
+// prompt renders the session's prompt template for its current state
+func (s *Session) prompt() string {
+	if s.promptTemplate == nil {
+		return DefaultPrompt
+	}
+
+	data := promptData{
+		Path:      s.RootQuery().Path(),
+		Type:      s.CurrentQuery().ConcreteType,
+		Operation: s.RootQuery().Name,
+	}
+
+	if endpoint, err := url.Parse(s.endpoint); err == nil {
+		data.Host = endpoint.Host
+	}
+
+	var prompt strings.Builder
+	if err := s.promptTemplate.Execute(&prompt, data); err != nil {
+		return DefaultPrompt
+	}
+
+	return prompt.String()
+}
+
+// truncateLeft truncates a string to at most n characters, keeping its end
+func truncateLeft(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	runes := []rune(s)
+	return "…" + string(runes[len(runes)-n+1:])
+}
@@ -0,0 +1,39 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrompt(t *testing.T) {
+	s := newTestSession(t)
+	s.endpoint = "https://api.github.com/graphql"
+
+	assert.Equal(t, DefaultPrompt, s.prompt())
+
+	tmpl, err := parsePrompt("{{.Host}} {{.Operation}} {{truncate 12 .Path}}{{with .Type}} on {{.}}{{end}} › ")
+	require.NoError(t, err)
+	s.promptTemplate = tmpl
+
+	assert.Equal(t, "api.github.com query .query › ", s.prompt())
+
+	queries, err := querybuilder.ParsePath(`.repository(owner: "jclem", name: "graphsh").object`)
+	require.NoError(t, err)
+	s.rootQuery.AddChild(queries[0])
+	s.currentQuery = queries[1]
+	s.currentQuery.ConcreteType = "Commit"
+
+	assert.Equal(t, "api.github.com query …em\").object on Commit › ", s.prompt())
+
+	_, err = parsePrompt("{{.Path")
+	assert.Error(t, err)
+}
+
+func TestTruncateLeft(t *testing.T) {
+	assert.Equal(t, ".query", truncateLeft(10, ".query"))
+	assert.Equal(t, "…ery", truncateLeft(4, ".query"))
+	assert.Equal(t, ".query", truncateLeft(0, ".query"))
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jclem/graphsh/command"
+)
+
+// ErrCommandFailed is returned when a command run non-interactively fails,
+// after its error has been printed
+var ErrCommandFailed = errors.New("Command failed")
+
+// RunScript executes each line read from a script as a command, without
+// prompting. Blank lines and lines starting with "#" are skipped.
+//
+// Errors are printed to stderr with the line they occurred on, except for
+// GraphQL errors, which are printed along with the response. Execution stops
+// at the first failing command unless keepGoing is set, and also stops,
+// successfully, at an `exit` command.
+func (s *Session) RunScript(name string, r io.Reader, keepGoing bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	failed := false
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := s.execInput(line); err == command.ErrExit {
+			return nil
+		} else if err != nil {
+			// GraphQL errors have already been printed along with the response
+			if err != command.ErrGraphQL {
+				fmt.Fprintf(os.Stderr, "%s:%d: %s\n", name, lineNumber, err)
+			}
+
+			if !keepGoing {
+				return ErrCommandFailed
+			}
+
+			failed = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if failed {
+		return ErrCommandFailed
+	}
+
+	return nil
+}
+
+// RunCommands executes each of the given commands in order, without prompting
+//
+// Errors are printed to stderr as in RunScript, and execution stops at the
+// first failing command unless keepGoing is set. Execution also stops,
+// successfully, at an `exit` command.
+func (s *Session) RunCommands(commands []string, keepGoing bool) error {
+	failed := false
+
+	for _, input := range commands {
+		if err := s.execInput(input); err == command.ErrExit {
+			return nil
+		} else if err != nil {
+			if err != command.ErrGraphQL {
+				fmt.Fprintln(os.Stderr, err)
+			}
+
+			if !keepGoing {
+				return ErrCommandFailed
+			}
+
+			failed = true
+		}
+	}
+
+	if failed {
+		return ErrCommandFailed
+	}
+
+	return nil
+}
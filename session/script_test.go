@@ -0,0 +1,128 @@
+package session
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jclem/graphsh/command"
+	"github.com/jclem/graphsh/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testScript = `
+# Set some variables
+set $a 1
+bogus
+set $b 2
+`
+
+func TestRunScript(t *testing.T) {
+	s := newTestSession(t)
+
+	err := s.RunScript("test.gsh", strings.NewReader(testScript), false)
+	assert.Equal(t, ErrCommandFailed, err)
+	assert.Contains(t, s.Variables(), "a")
+	assert.NotContains(t, s.Variables(), "b")
+}
+
+func TestRunScriptKeepGoing(t *testing.T) {
+	s := newTestSession(t)
+
+	err := s.RunScript("test.gsh", strings.NewReader(testScript), true)
+	assert.Equal(t, ErrCommandFailed, err)
+	assert.Contains(t, s.Variables(), "a")
+	assert.Contains(t, s.Variables(), "b")
+
+	assert.NoError(t, s.RunScript("test.gsh", strings.NewReader("set $c 3\n\n"), false))
+}
+
+func TestRunScriptExit(t *testing.T) {
+	s := newTestSession(t)
+
+	assert.NoError(t, s.RunScript("test.gsh", strings.NewReader("set $a 1\nexit\nbogus\n"), false))
+	assert.Contains(t, s.Variables(), "a")
+}
+
+func TestRunCommands(t *testing.T) {
+	s := newTestSession(t)
+
+	assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"set $a 1", "bogus", "set $b 2"}, false))
+	assert.Contains(t, s.Variables(), "a")
+	assert.NotContains(t, s.Variables(), "b")
+
+	assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"bogus", "set $c 3"}, true))
+	assert.Contains(t, s.Variables(), "c")
+
+	assert.NoError(t, s.RunCommands([]string{"exit", "bogus"}, false))
+}
+
+// stubQuerier responds to every query with the same response body, and
+// records the queries it receives
+type stubQuerier struct {
+	response string
+	queries  []string
+}
+
+func (q *stubQuerier) Query(query string, variables map[string]interface{}) (*graphql.Response, error) {
+	q.queries = append(q.queries, query)
+	return graphql.ParseResponse([]byte(q.response))
+}
+
+// outputMu serializes capturing stdout and stderr, which parallel tests share
+var outputMu sync.Mutex
+
+// captureOutput gets what a function writes to stdout or stderr
+func captureOutput(t *testing.T, file **os.File, fn func()) string {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	original := *file
+	*file = w
+	defer func() { *file = original }()
+
+	fn()
+	w.Close()
+
+	output, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(output)
+}
+
+func captureStderr(t *testing.T, fn func()) string {
+	return captureOutput(t, &os.Stderr, fn)
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	return captureOutput(t, &os.Stdout, fn)
+}
+
+func TestRunGraphQLErrors(t *testing.T) {
+	s := newTestSession(t)
+	s.client = &stubQuerier{response: `{"data": null, "errors": [{"message": "Something went wrong"}]}`}
+
+	// GraphQL errors fail the run, but are only printed once, with the response
+	var err error
+	stderr := captureStderr(t, func() {
+		err = s.RunCommands([]string{"{viewer {login}}", "set $a 1"}, true)
+	})
+	assert.Equal(t, ErrCommandFailed, err)
+	assert.Contains(t, s.Variables(), "a")
+	assert.Equal(t, 1, strings.Count(stderr, "Something went wrong"))
+	assert.NotContains(t, stderr, command.ErrGraphQL.Error())
+
+	stderr = captureStderr(t, func() {
+		err = s.RunScript("test.gsh", strings.NewReader("{viewer {login}}\nset $b 2\n"), false)
+	})
+	assert.Equal(t, ErrCommandFailed, err)
+	assert.NotContains(t, s.Variables(), "b")
+	assert.Contains(t, stderr, "Something went wrong")
+	assert.NotContains(t, stderr, "test.gsh:1:")
+}
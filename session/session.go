@@ -2,12 +2,14 @@ package session
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/textproto"
 	"os"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/jclem/graphsh/command"
@@ -21,15 +23,55 @@ type (
 	Options struct {
 		Endpoint string
 		Headers  []string
+
+		// SubscriptionEndpoint is the WebSocket endpoint for subscriptions,
+		// which defaults to Endpoint
+		SubscriptionEndpoint string
+
+		// SchemaFile is a file to load the schema from instead of introspecting
+		// the endpoint, see introspection.LoadSchemaFile
+		SchemaFile string
+
+		// NoCache disables the schema cache, so that the schema is always
+		// introspected
+		NoCache bool
+
+		// SchemaTTL is how long a cached schema is used before it is
+		// introspected again, with zero always introspecting it but still
+		// falling back to the cache if introspection fails
+		SchemaTTL time.Duration
+
+		// Prompt is a text/template for the prompt, see promptData for the
+		// values available to it
+		Prompt string
+
+		// AssumeYes answers yes to confirmations, such as before executing a
+		// mutation, which is required to confirm outside of interactive use
+		AssumeYes bool
+
+		// HistorySkipSecrets prevents lines that look like they contain
+		// secrets, such as headers, from being persisted to the history file
+		HistorySkipSecrets bool
 	}
 
 	// Session represents a shell session
 	Session struct {
 		client       graphql.Querier
+		subscriber   graphql.Subscriber
 		endpoint     string
 		headers      []string
 		rootQuery    *querybuilder.Query
 		currentQuery *querybuilder.Query
+		variables    map[string]querybuilder.Value
+		history      []string
+		schema       *introspection.Schema
+
+		schemaFile         string
+		schemaCachePath    string
+		reader             *readline.Instance
+		promptTemplate     *template.Template
+		assumeYes          bool
+		historySkipSecrets bool
 	}
 )
 
@@ -38,6 +80,11 @@ func (s Session) Client() graphql.Querier {
 	return s.client
 }
 
+// Subscriber implements types.Session
+func (s Session) Subscriber() graphql.Subscriber {
+	return s.subscriber
+}
+
 // Endpoint implements types.Session
 func (s Session) Endpoint() string {
 	return s.endpoint
@@ -53,6 +100,11 @@ func (s Session) RootQuery() *querybuilder.Query {
 	return s.rootQuery
 }
 
+// SetRootQuery implements types.Session
+func (s *Session) SetRootQuery(query *querybuilder.Query) {
+	s.rootQuery = query
+}
+
 // CurrentQuery implements types.Session
 func (s Session) CurrentQuery() *querybuilder.Query {
 	return s.currentQuery
@@ -63,49 +115,187 @@ func (s *Session) SetCurrentQuery(query *querybuilder.Query) {
 	s.currentQuery = query
 }
 
+// Schema implements types.Session
+func (s Session) Schema() *introspection.Schema {
+	return s.schema
+}
+
+// Variables implements types.Session
+func (s Session) Variables() map[string]querybuilder.Value {
+	return s.variables
+}
+
+// SetVariable implements types.Session
+func (s *Session) SetVariable(name string, value querybuilder.Value) {
+	s.variables[name] = value
+}
+
+// UnsetVariable implements types.Session
+func (s *Session) UnsetVariable(name string) {
+	delete(s.variables, name)
+}
+
+// History implements types.Session
+func (s Session) History() []string {
+	return s.history
+}
+
+// Interactive implements types.Session, and is whether the session is reading
+// commands from the user rather than from a script or the command line
+func (s *Session) Interactive() bool {
+	return s.reader != nil
+}
+
+// Confirm implements types.Session
+func (s *Session) Confirm(prompt string) (bool, error) {
+	if s.assumeYes {
+		return true, nil
+	}
+
+	if s.reader == nil {
+		return false, errors.New("Unable to confirm outside of an interactive session, pass --yes to confirm")
+	}
+
+	s.reader.SetPrompt(fmt.Sprintf("%s [y/N] ", prompt))
+	defer s.reader.SetPrompt(s.prompt())
+
+	answer, err := s.reader.Readline()
+	if err == readline.ErrInterrupt || err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// RefreshSchema implements types.Session
+func (s *Session) RefreshSchema() error {
+	var schema *introspection.Schema
+	var err error
+
+	if s.schemaFile != "" {
+		schema, err = introspection.LoadSchemaFile(s.schemaFile)
+	} else {
+		schema, err = introspection.RefreshSchema(s.client, s.schemaCachePath)
+	}
+
+	// A schema is returned even if the cache could not be written
+	if schema != nil {
+		s.schema = schema
+	}
+
+	return err
+}
+
 // NewSession creates a new session
 func NewSession(options Options) (*Session, error) {
-	headers, err := parseHeaders(options.Headers)
+	headers, err := ParseHeaders(options.Headers)
 	if err != nil {
 		return nil, err
 	}
 
 	client := graphql.New(options.Endpoint, headers)
+
+	subscriptionEndpoint := options.SubscriptionEndpoint
+	if subscriptionEndpoint == "" {
+		subscriptionEndpoint = options.Endpoint
+	}
 	query := querybuilder.NewRootQuery()
 
-	// Load the schema for this session
-	if err := introspection.LoadSchema(client); err != nil {
+	promptText := options.Prompt
+	if promptText == "" {
+		promptText = DefaultPrompt
+	}
+
+	promptTemplate, err := parsePrompt(promptText)
+	if err != nil {
 		return nil, err
 	}
 
+	// Load the schema for this session
+	var schema *introspection.Schema
+	var cachePath string
+
+	switch {
+	case options.SchemaFile != "":
+		if schema, err = introspection.LoadSchemaFile(options.SchemaFile); err != nil {
+			return nil, err
+		}
+	case options.NoCache:
+		if schema, err = introspection.LoadSchema(client); err != nil {
+			return nil, err
+		}
+	default:
+		if cachePath, err = schemaCachePath(options.Endpoint, headers); err != nil {
+			return nil, err
+		}
+
+		if schema, err = introspection.LoadCachedSchema(client, cachePath, options.SchemaTTL); err != nil {
+			if _, ok := err.(*introspection.StaleSchemaError); !ok {
+				return nil, err
+			}
+
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+
 	return &Session{
 		client:       client,
+		subscriber:   graphql.NewSubscriber(subscriptionEndpoint, headers),
 		endpoint:     options.Endpoint,
 		headers:      options.Headers,
 		rootQuery:    query,
 		currentQuery: query,
+		variables:    map[string]querybuilder.Value{},
+		schema:       schema,
+
+		schemaFile:         options.SchemaFile,
+		schemaCachePath:    cachePath,
+		promptTemplate:     promptTemplate,
+		assumeYes:          options.AssumeYes,
+		historySkipSecrets: options.HistorySkipSecrets,
 	}, nil
 }
 
-func parseHeaders(headers []string) (map[string][]string, error) {
+// ParseHeaders parses headers given as "Name: value" strings
+func ParseHeaders(headers []string) (map[string][]string, error) {
 	headerString := fmt.Sprintf("%s\r\n\r\n", strings.Join(headers, "\r\n"))
 	reader := bufio.NewReader(strings.NewReader((headerString)))
 	tp := textproto.NewReader(reader)
 	return tp.ReadMIMEHeader()
 }
 
-// Loop starts a session loop to react to user input, using a default prompt
-func Loop(options Options) {
-	s, err := NewSession(options)
-
+// Loop starts a session loop to react to user input, returning when the user
+// exits the shell
+func (s *Session) Loop() error {
 	isInterrupting := false
 
-	reader, err := readline.New("› ")
+	historyFile, err := historyPath(s.endpoint)
+	if err == nil {
+		s.history, err = loadHistory(historyFile)
+	}
 	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintf(os.Stderr, "History will not be saved: %s\n", err)
+		historyFile = ""
+	}
+
+	reader, err := readline.NewEx(&readline.Config{
+		Prompt:                 s.prompt(),
+		AutoComplete:           completer{s},
+		HistoryFile:            historyFile,
+		HistoryLimit:           historyLimit,
+		DisableAutoSaveHistory: true,
+	})
+	if err != nil {
+		return err
 	}
 	defer reader.Close()
 
+	s.reader = reader
+	defer func() { s.reader = nil }()
+
 	for {
 		wasInterrupting := isInterrupting
 		isInterrupting = false
@@ -114,7 +304,7 @@ func Loop(options Options) {
 		if err != nil {
 			if err == readline.ErrInterrupt {
 				if wasInterrupting {
-					os.Exit(0)
+					return nil
 				}
 
 				isInterrupting = true
@@ -123,17 +313,48 @@ func Loop(options Options) {
 			}
 
 			if err == io.EOF {
-				os.Exit(0)
+				return nil
 			}
 
 			fmt.Fprintln(os.Stderr, err)
 			continue
 		}
 
-		if err := s.execInput(line); err != nil {
+		input, err := expandHistory(line, s.history)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+
+		if input != line {
+			fmt.Println(input)
+		}
+
+		s.recordHistory(reader, input)
+
+		// GraphQL errors have already been printed along with the response
+		if err := s.execInput(input); err == command.ErrExit {
+			return nil
+		} else if err != nil && err != command.ErrGraphQL {
 			fmt.Fprintln(os.Stderr, err)
 		}
+
+		reader.SetPrompt(s.prompt())
+	}
+}
+
+func (s *Session) recordHistory(reader *readline.Instance, line string) {
+	if strings.TrimSpace(line) == "" {
+		return
 	}
+
+	s.history = appendHistory(s.history, line)
+
+	if s.historySkipSecrets && isSecret(line) {
+		return
+	}
+
+	reader.SaveHistory(line)
 }
 
 func (s *Session) execInput(line string) error {
@@ -0,0 +1,232 @@
+package session
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/chzyer/readline"
+
+	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/introspection"
+	"github.com/jclem/graphsh/querybuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationMode(t *testing.T) {
+	s := newTestSession(t)
+
+	require.NoError(t, s.RunCommands([]string{"mutation", `.addStar(input: {starrableId: "abc"})`}, false))
+	assert.Equal(t, "mutation", s.RootQuery().Name)
+	assert.Equal(t, `.mutation.addStar(input: {starrableId: "abc"})`, s.RootQuery().Path())
+
+	typ, err := s.Schema().GetType(s.RootQuery())
+	require.NoError(t, err)
+	assert.Equal(t, "AddStarPayload", typ.Name)
+
+	client := &stubQuerier{response: `{"data": {"addStar": {"clientMutationId": "1"}}}`}
+	s.client = client
+
+	// Mutations can not be confirmed non-interactively without --yes
+	assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"{clientMutationId}"}, false))
+	assert.Empty(t, client.queries)
+
+	// Declined mutations are not executed
+	s.reader = newTestReader(t, "n\n")
+	assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"{clientMutationId}"}, false))
+	assert.Empty(t, client.queries)
+
+	s.reader = newTestReader(t, "y\n")
+	output := captureStdout(t, func() {
+		err = s.RunCommands([]string{"{clientMutationId}"}, false)
+	})
+	assert.NoError(t, err)
+	require.Len(t, client.queries, 1)
+	assert.True(t, strings.HasPrefix(client.queries[0], "mutation {"), client.queries[0])
+	assert.Contains(t, client.queries[0], `addStar(input: {starrableId: "abc"})`)
+	assert.Contains(t, client.queries[0], "clientMutationId")
+	assert.Contains(t, output, `"clientMutationId": "1"`)
+
+	s.reader = nil
+	s.assumeYes = true
+	captureStdout(t, func() {
+		err = s.RunCommands([]string{"{clientMutationId}"}, false)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, client.queries, 2)
+
+	require.NoError(t, s.RunCommands([]string{"query"}, false))
+	assert.Equal(t, ".query", s.RootQuery().Path())
+	assert.Equal(t, s.RootQuery(), s.CurrentQuery())
+}
+
+// newTestReader creates a readline instance that reads the given input
+func newTestReader(t *testing.T, input string) *readline.Instance {
+	reader, err := readline.NewEx(&readline.Config{
+		Stdin:          ioutil.NopCloser(strings.NewReader(input)),
+		Stdout:         ioutil.Discard,
+		FuncIsTerminal: func() bool { return false },
+	})
+	require.NoError(t, err)
+
+	return reader
+}
+
+// stubSubscriber completes every subscription without any events, and records
+// the queries it receives
+type stubSubscriber struct {
+	queries []string
+}
+
+func (c *stubSubscriber) Subscribe(ctx context.Context, query string, variables map[string]interface{}, handler func(*graphql.Response) error) error {
+	c.queries = append(c.queries, query)
+	return nil
+}
+
+func TestSubscriptionHint(t *testing.T) {
+	s := newTestSession(t)
+	subscriber := &stubSubscriber{}
+	s.subscriber = subscriber
+
+	require.NoError(t, s.RunCommands([]string{"subscription", `.issueUpdated(id: "1")`}, false))
+
+	var err error
+	output := captureStderr(t, func() {
+		err = s.RunCommands([]string{"{title}"}, false)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, subscriber.queries, 1)
+	assert.NotContains(t, output, "press ^C to stop")
+
+	s.reader = newTestReader(t, "")
+	output = captureStderr(t, func() {
+		err = s.RunCommands([]string{"{title}"}, false)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, subscriber.queries, 2)
+	assert.Contains(t, output, "Subscribed, press ^C to stop")
+}
+
+func TestTraverseValidation(t *testing.T) {
+	s := newTestSession(t)
+
+	require.NoError(t, s.RunCommands([]string{`.repository(owner: "jclem", name: "graphsh")`}, false))
+	current := s.CurrentQuery()
+
+	for _, path := range []string{".nmae", ".owner.login", ".issues(first: \"ten\")", ".issues(frist: 10)"} {
+		assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{path}, false), path)
+		assert.Equal(t, current, s.CurrentQuery(), path)
+		assert.Nil(t, current.Child(), path)
+	}
+
+	require.NoError(t, s.RunCommands([]string{".issues(first: 10)"}, false))
+	assert.Equal(t, `.query.repository(name: "graphsh", owner: "jclem").issues(first: 10)`, s.RootQuery().Path())
+}
+
+func TestQueryValidation(t *testing.T) {
+	s := newTestSession(t)
+	client := &stubQuerier{response: `{"data": {"repository": {"name": "graphsh"}}}`}
+	s.client = client
+
+	require.NoError(t, s.RunCommands([]string{"set $owner 1", `.repository(owner: $owner, name: "graphsh")`}, false))
+
+	var err error
+	output := captureStderr(t, func() {
+		err = s.RunCommands([]string{"{name}", "{ }"}, true)
+	})
+	assert.Equal(t, ErrCommandFailed, err)
+	assert.Contains(t, output, "Variable $owner expects String!, but got 1")
+	assert.Contains(t, output, `Field "repository" of type Repository must have a selection of subfields`)
+	assert.Empty(t, client.queries)
+}
+
+func TestOn(t *testing.T) {
+	s := newTestSession(t)
+
+	require.NoError(t, s.RunCommands([]string{`.repository(owner: "jclem", name: "graphsh").object`}, false))
+
+	assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"on Issue"}, false))
+	assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"on Nope"}, false))
+	assert.Empty(t, s.CurrentQuery().ConcreteType)
+
+	require.NoError(t, s.RunCommands([]string{"on ?", "on Commit"}, false))
+	assert.Equal(t, "Commit", s.CurrentQuery().ConcreteType)
+
+	require.NoError(t, s.RunCommands([]string{"on"}, false))
+	assert.Empty(t, s.CurrentQuery().ConcreteType)
+
+	// Object types can be narrowed to the interfaces they implement
+	require.NoError(t, s.RunCommands([]string{"..", "on Node"}, false))
+	assert.Equal(t, "Node", s.CurrentQuery().ConcreteType)
+}
+
+func TestPathTo(t *testing.T) {
+	s := newTestSession(t)
+
+	require.NoError(t, s.RunCommands([]string{"path-to Commit", "path-to -n 1 Blob"}, false))
+	assert.Equal(t, ".query", s.RootQuery().Path())
+
+	for _, input := range []string{"path-to Query", "path-to String", "path-to SearchResultItem 2", "path-to -n 0 Commit"} {
+		assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{input}, false), input)
+	}
+
+	// Traversing a path applies its concrete types
+	require.NoError(t, s.RunCommands([]string{"path-to Commit 3"}, false))
+	assert.Equal(t, ".query.node(id: $id).object", s.RootQuery().Path())
+	assert.Equal(t, "Repository", s.RootQuery().Child().ConcreteType)
+	assert.Equal(t, "Commit", s.CurrentQuery().ConcreteType)
+
+	// Paths can start by applying a concrete type to the current node
+	require.NoError(t, s.RunCommands([]string{"..", "on", "path-to Repository 1"}, false))
+	assert.Equal(t, ".query.node(id: $id)", s.RootQuery().Path())
+	assert.Equal(t, "Repository", s.CurrentQuery().ConcreteType)
+}
+
+func TestSessionSchemas(t *testing.T) {
+	tests := []struct {
+		name       string
+		schemaFile string
+		field      string
+		otherField string
+	}{
+		{"GitHub", "../introspection/testdata/schema.json", "viewer", "greeting"},
+		{"Greetings", "testdata/greetings.graphql", "greeting", "viewer"},
+	}
+
+	// Sessions with different schemas run side by side without affecting
+	// each other
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := introspection.LoadSchemaFile(test.schemaFile)
+			require.NoError(t, err)
+
+			s := newTestSession(t)
+			s.schema = schema
+
+			for i := 0; i < 10; i++ {
+				var err error
+				output := captureStdout(t, func() {
+					err = s.RunCommands([]string{"ls"}, false)
+				})
+				require.NoError(t, err)
+				assert.Contains(t, output, test.field)
+				assert.NotContains(t, output, test.otherField)
+
+				selections, err := querybuilder.ParseSelections(test.field + " { __typename }")
+				require.NoError(t, err)
+				assert.NoError(t, s.Schema().Validate(s.RootQuery(), selections, nil))
+
+				assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"." + test.otherField}, false))
+				assert.Equal(t, ErrCommandFailed, s.RunCommands([]string{"{" + test.otherField + " { __typename }}"}, false))
+
+				require.NoError(t, s.RunCommands([]string{"." + test.field, ".."}, false))
+			}
+		})
+	}
+}
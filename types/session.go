@@ -2,15 +2,26 @@ package types
 
 import (
 	"github.com/jclem/graphsh/graphql"
+	"github.com/jclem/graphsh/introspection"
 	"github.com/jclem/graphsh/querybuilder"
 )
 
 // Session gets session information
 type Session interface {
 	Client() graphql.Querier
+	Subscriber() graphql.Subscriber
 	Endpoint() string
 	Headers() []string
 	RootQuery() *querybuilder.Query
+	SetRootQuery(q *querybuilder.Query)
 	CurrentQuery() *querybuilder.Query
 	SetCurrentQuery(q *querybuilder.Query)
+	Schema() *introspection.Schema
+	Variables() map[string]querybuilder.Value
+	SetVariable(name string, value querybuilder.Value)
+	UnsetVariable(name string)
+	History() []string
+	Interactive() bool
+	Confirm(prompt string) (bool, error)
+	RefreshSchema() error
 }